@@ -0,0 +1,67 @@
+package sno
+
+// Arena vends IDs generated into a pre-allocated backing array, so that bursts of generation
+// do not touch the heap, e.g. in latency-critical producers sensitive to GC pressure.
+//
+// An Arena must be constructed using NewArena. Once the backing array is full, the Arena either
+// grows (allocating a new backing array of twice the size) or wraps around to its beginning,
+// overwriting the oldest IDs - depending on how it got constructed.
+//
+// The pointers returned by Arena.New point into the backing array, so they are only valid until
+// the Arena gets Reset or - when wrapping - until it wraps around to their position, at which point
+// they silently alias newer IDs. Growing leaves previously returned pointers valid (they keep
+// the previous backing array alive), but the Arena no longer reuses that memory.
+//
+// An Arena is not safe for concurrent use - use one per goroutine instead.
+type Arena struct {
+	ids  []ID
+	next int // Index the next ID gets generated into.
+	grow bool
+}
+
+// NewArena returns a new Arena with a backing array of size IDs, which grows once full if grow
+// is true and wraps around otherwise. It panics with an InvalidSizeError if size <= 0.
+func NewArena(size int, grow bool) *Arena {
+	if size <= 0 {
+		panic(&InvalidSizeError{Func: "NewArena", Size: size})
+	}
+
+	return &Arena{
+		ids:  make([]ID, size),
+		grow: grow,
+	}
+}
+
+// New generates a new ID like g.New does, but into the Arena, and returns a pointer to it.
+func (a *Arena) New(g *Generator, meta byte) *ID {
+	if a.next == len(a.ids) {
+		if a.grow {
+			a.ids = make([]ID, 2*len(a.ids))
+		}
+
+		a.next = 0
+	}
+
+	id := &a.ids[a.next]
+	*id = g.New(meta)
+	a.next++
+
+	return id
+}
+
+// Len returns the number of IDs generated into the current backing array since the Arena got
+// constructed, last Reset, last grew or last wrapped around.
+func (a *Arena) Len() int {
+	return a.next
+}
+
+// Cap returns the capacity of the current backing array.
+func (a *Arena) Cap() int {
+	return len(a.ids)
+}
+
+// Reset makes the Arena reuse its backing array from its beginning. All pointers previously
+// returned by the Arena become invalid.
+func (a *Arena) Reset() {
+	a.next = 0
+}
@@ -0,0 +1,96 @@
+package sno
+
+import "testing"
+
+func TestArena_Wrap(t *testing.T) {
+	g, err := NewGenerator(&GeneratorSnapshot{
+		Partition: Partition{'A', 'R'},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := NewArena(4, false)
+
+	ptrs := make([]*ID, 6)
+	for i := range ptrs {
+		ptrs[i] = a.New(g, 255)
+
+		if actual, expected := ptrs[i].Partition(), g.Partition(); actual != expected {
+			t.Errorf("%d: expected [%v], got [%v]", i, expected, actual)
+		}
+	}
+
+	if actual, expected := a.Cap(), 4; actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	if actual, expected := a.Len(), 2; actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	// Wrapping reuses the backing array - the oldest pointers now alias the newest IDs.
+	if ptrs[0] != ptrs[4] || ptrs[1] != ptrs[5] {
+		t.Error("expected the arena to wrap around")
+	}
+
+	if ptrs[4].Compare(*ptrs[3]) <= 0 {
+		t.Errorf("expected [%s] to sort after [%s]", *ptrs[4], *ptrs[3])
+	}
+
+	a.Reset()
+
+	if actual, expected := a.Len(), 0; actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	if a.New(g, 255) != ptrs[0] {
+		t.Error("expected the arena to reuse its backing array after a reset")
+	}
+}
+
+func TestArena_Grow(t *testing.T) {
+	g, err := NewGenerator(&GeneratorSnapshot{
+		Partition: Partition{'A', 'G'},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		a    = NewArena(4, true)
+		ptrs = make([]*ID, 6)
+		ids  = make([]ID, 6)
+	)
+
+	for i := range ptrs {
+		ptrs[i] = a.New(g, 255)
+		ids[i] = *ptrs[i]
+	}
+
+	if actual, expected := a.Cap(), 8; actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	// Growing leaves previously returned pointers valid.
+	for i := range ptrs {
+		if *ptrs[i] != ids[i] {
+			t.Errorf("%d: expected [%s], got [%s]", i, ids[i], *ptrs[i])
+		}
+	}
+}
+
+func TestArena_InvalidSize(t *testing.T) {
+	defer func() {
+		err, ok := recover().(*InvalidSizeError)
+		if !ok {
+			t.Fatalf("expected a panic with [%T]", &InvalidSizeError{})
+		}
+
+		if actual, expected := err.Func, "NewArena"; actual != expected {
+			t.Errorf("expected [%s], got [%s]", expected, actual)
+		}
+	}()
+
+	NewArena(0, false)
+}
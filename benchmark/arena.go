@@ -0,0 +1,52 @@
+package benchmark
+
+import (
+	"testing"
+
+	"github.com/muyo/sno"
+)
+
+const arenaBurst = 1024
+
+func benchmarkArena(b *testing.B) {
+	println("\n-- Arena (bursts of 1024 IDs) ----------------------------------------------------------------\n")
+	b.Run("slice", benchmarkArenaSlice)
+	b.Run("arena", benchmarkArenaArena)
+}
+
+func benchmarkArenaSlice(b *testing.B) {
+	g, err := sno.NewGenerator(nil, nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		var ids []sno.ID
+		for j := 0; j < arenaBurst; j++ {
+			ids = append(ids, g.New(255))
+		}
+	}
+}
+
+func benchmarkArenaArena(b *testing.B) {
+	g, err := sno.NewGenerator(nil, nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	a := sno.NewArena(arenaBurst, false)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < arenaBurst; j++ {
+			_ = a.New(g, 255)
+		}
+
+		a.Reset()
+	}
+}
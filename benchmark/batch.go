@@ -0,0 +1,46 @@
+package benchmark
+
+import (
+	"testing"
+
+	"github.com/muyo/sno"
+)
+
+const batchSize = 1024
+
+func benchmarkBatch(b *testing.B) {
+	println("\n-- Batch (1024 IDs per op) ---------------------------------------------------------------------\n")
+	b.Run("loop", benchmarkBatchLoop)
+	b.Run("batch", benchmarkBatchBatch)
+}
+
+func benchmarkBatchLoop(b *testing.B) {
+	g, err := sno.NewGenerator(nil, nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		ids := make([]sno.ID, batchSize)
+		for j := range ids {
+			ids[j] = g.New(255)
+		}
+	}
+}
+
+func benchmarkBatchBatch(b *testing.B) {
+	g, err := sno.NewGenerator(nil, nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_ = g.NewBatch(255, batchSize)
+	}
+}
@@ -7,4 +7,9 @@ import (
 func Benchmark(b *testing.B) {
 	b.Run("generation", benchmarkGeneration)
 	b.Run("encoding", benchmarkEncoding)
+	b.Run("sql", benchmarkSQL)
+	b.Run("arena", benchmarkArena)
+	b.Run("batch", benchmarkBatch)
+	b.Run("sharded", benchmarkSharded)
+	b.Run("diff", benchmarkDiff)
 }
@@ -0,0 +1,73 @@
+package benchmark
+
+import (
+	"testing"
+
+	"github.com/muyo/sno"
+)
+
+const diffSize = 100000
+
+func benchmarkDiff(b *testing.B) {
+	println("\n-- Diff (2x 100k IDs, half overlapping) ------------------------------------------------------\n")
+	b.Run("merge", benchmarkDiffMerge)
+	b.Run("map", benchmarkDiffMap)
+}
+
+// diffInputs returns two sorted sets of IDs, the second half of a being the first half of b.
+func diffInputs() (a, b []sno.ID) {
+	ids := make([]sno.ID, diffSize+diffSize/2)
+	for i := range ids {
+		ids[i] = sno.New(255)
+	}
+
+	return ids[:diffSize], ids[diffSize/2:]
+}
+
+func benchmarkDiffMerge(b *testing.B) {
+	x, y := diffInputs()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, _, _ = sno.Diff(x, y)
+	}
+}
+
+func benchmarkDiffMap(b *testing.B) {
+	x, y := diffInputs()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		var (
+			inA                = make(map[sno.ID]struct{}, len(x))
+			inB                = make(map[sno.ID]struct{}, len(y))
+			onlyA, onlyB, both []sno.ID
+		)
+
+		for _, id := range x {
+			inA[id] = struct{}{}
+		}
+
+		for _, id := range y {
+			inB[id] = struct{}{}
+
+			if _, ok := inA[id]; ok {
+				both = append(both, id)
+			} else {
+				onlyB = append(onlyB, id)
+			}
+		}
+
+		for _, id := range x {
+			if _, ok := inB[id]; !ok {
+				onlyA = append(onlyA, id)
+			}
+		}
+
+		_, _, _ = onlyA, onlyB, both
+	}
+}
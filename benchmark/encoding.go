@@ -19,6 +19,38 @@ func benchmarkEncoding(b *testing.B) {
 	b.Run("enc", benchmarkEncode)
 	println("\n-- Decoding ----------------------------------------------------------------------------------\n")
 	b.Run("dec", benchmarkDecode)
+	println("\n-- Encoding into a buffer (sno) --------------------------------------------------------------\n")
+	b.Run("buf", benchmarkEncodeBuffer)
+}
+
+// encodeSink keeps the results of the buffer benchmarks alive, as they would be in actual use -
+// MarshalText would otherwise get away without allocating.
+var encodeSink []byte
+
+func benchmarkEncodeBuffer(b *testing.B) {
+	b.Run("marshal", benchmarkEncodeBufferMarshal)
+	b.Run("append", benchmarkEncodeBufferAppend)
+}
+
+func benchmarkEncodeBufferMarshal(b *testing.B) {
+	id := sno.New(255)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		encodeSink, _ = id.MarshalText()
+	}
+}
+
+func benchmarkEncodeBufferAppend(b *testing.B) {
+	id := sno.New(255)
+	encodeSink = make([]byte, 0, sno.SizeEncoded)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		encodeSink = id.AppendEncoded(encodeSink[:0])
+	}
 }
 
 func benchmarkEncode(b *testing.B) {
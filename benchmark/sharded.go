@@ -0,0 +1,45 @@
+package benchmark
+
+import (
+	"testing"
+
+	"github.com/muyo/sno"
+)
+
+// The difference only shows with enough parallelism to contend over a single Generator, e.g. when run
+// with -cpu 16 (or higher) on a machine with as many cores.
+func benchmarkSharded(b *testing.B) {
+	println("\n-- Sharded (parallel) ------------------------------------------------------------------------\n")
+	b.Run("plain", benchmarkShardedPlain)
+	b.Run("sharded", benchmarkShardedSharded)
+}
+
+func benchmarkShardedPlain(b *testing.B) {
+	g, err := sno.NewGenerator(nil, nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = g.New(255)
+		}
+	})
+}
+
+func benchmarkShardedSharded(b *testing.B) {
+	g, err := sno.NewShardedGenerator(nil, nil, 0, nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = g.New(255)
+		}
+	})
+}
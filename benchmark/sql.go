@@ -0,0 +1,94 @@
+package benchmark
+
+import (
+	"testing"
+
+	"github.com/muyo/sno"
+)
+
+func benchmarkSQL(b *testing.B) {
+	println("\n-- SQL (driver interfaces vs raw) -------------------------------------------------------------\n")
+	b.Run("scan", benchmarkScan)
+	b.Run("value", benchmarkValue)
+}
+
+func benchmarkScan(b *testing.B) {
+	b.Run("binary", benchmarkScanBinary)
+	b.Run("binary-raw", benchmarkScanBinaryRaw)
+	b.Run("string", benchmarkScanString)
+	b.Run("string-raw", benchmarkScanStringRaw)
+}
+
+func benchmarkScanBinary(b *testing.B) {
+	src := sno.New(255).Bytes()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		var id sno.ID
+		for pb.Next() {
+			_ = id.Scan(src)
+		}
+	})
+}
+
+func benchmarkScanBinaryRaw(b *testing.B) {
+	src := sno.New(255).Bytes()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		var id sno.ID
+		for pb.Next() {
+			_ = id.UnmarshalBinary(src)
+		}
+	})
+}
+
+func benchmarkScanString(b *testing.B) {
+	src := sno.New(255).String()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		var id sno.ID
+		for pb.Next() {
+			_ = id.Scan(src)
+		}
+	})
+}
+
+func benchmarkScanStringRaw(b *testing.B) {
+	src := sno.New(255).String()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_, _ = sno.FromEncodedString(src)
+		}
+	})
+}
+
+func benchmarkValue(b *testing.B) {
+	b.Run("driver", benchmarkValueDriver)
+	b.Run("raw", benchmarkValueRaw)
+}
+
+func benchmarkValueDriver(b *testing.B) {
+	id := sno.New(255)
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_, _ = id.Value()
+		}
+	})
+}
+
+func benchmarkValueRaw(b *testing.B) {
+	id := sno.New(255)
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_, _ = id.MarshalBinary()
+		}
+	})
+}
@@ -0,0 +1,136 @@
+package sno
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// manualClock is a Clock which only moves when told to.
+type manualClock uint64
+
+func (c *manualClock) now() uint64     { return atomic.LoadUint64((*uint64)(c)) }
+func (c *manualClock) set(wall uint64) { atomic.StoreUint64((*uint64)(c), wall) }
+
+func TestGenerator_Clock_Drift(t *testing.T) {
+	clock := manualClock(1000)
+
+	g, err := NewGeneratorWithOptions(&GeneratorSnapshot{
+		Partition: Partition{'C', 'K'},
+	}, &GeneratorOptions{Clock: clock.now}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id := g.New(255)
+	if actual, expected := uint64(id.Timestamp()-epochNsec)/TimeUnit, uint64(1000); actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	if id[4]&1 != 0 {
+		t.Errorf("expected the tick-tock bit to be unset")
+	}
+
+	// A regression gets tick-tocked, same as a regression of the wall clock.
+	clock.set(990)
+
+	id = g.New(255)
+	if actual, expected := uint64(id.Timestamp()-epochNsec)/TimeUnit, uint64(990); actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	if id[4]&1 != 1 {
+		t.Errorf("expected the tick-tock bit to be set")
+	}
+
+	snapshot := g.Snapshot()
+
+	if actual, expected := snapshot.Drifts, uint32(1); actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	if actual, expected := snapshot.WallSafe, int64(1000); actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	if actual, expected := snapshot.Now, int64(990); actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+}
+
+func TestGenerator_Clock_Sequence(t *testing.T) {
+	clock := manualClock(1000)
+
+	g, err := NewGeneratorWithOptions(&GeneratorSnapshot{
+		Partition:   Partition{'C', 'K'},
+		SequenceMin: 1024,
+		SequenceMax: 1039,
+	}, &GeneratorOptions{Clock: clock.now}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		g.New(255)
+	}
+
+	if actual, expected := g.Len(), 3; actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	if actual, expected := g.Sequence(), uint32(1026); actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	// Moving to the next timeframe resets the sequence, as seen by the clock rather than the wall clock.
+	clock.set(1001)
+
+	if actual, expected := g.Len(), 0; actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	if actual, expected := g.Sequence(), uint32(1024); actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	if actual, expected := g.New(255).Sequence(), uint16(1024); actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+}
+
+func TestGenerator_Clock_Overflow(t *testing.T) {
+	clock := manualClock(1000)
+
+	g, err := NewGeneratorWithOptions(&GeneratorSnapshot{
+		Partition:   Partition{'C', 'O'},
+		SequenceMin: 1024,
+		SequenceMax: 1039,
+	}, &GeneratorOptions{Clock: clock.now}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < g.Cap(); i++ {
+		g.New(255)
+	}
+
+	blocked := make(chan ID)
+	go func() {
+		blocked <- g.New(255)
+	}()
+
+	// The overflowing caller must get released once the clock progresses - even if nothing else
+	// calls the Generator in the meantime.
+	time.Sleep(10 * TimeUnit)
+	clock.set(1001)
+
+	select {
+	case id := <-blocked:
+		if actual, expected := uint64(id.Timestamp()-epochNsec)/TimeUnit, uint64(1001); actual != expected {
+			t.Errorf("expected [%d], got [%d]", expected, actual)
+		}
+
+	case <-time.After(time.Second):
+		t.Fatal("expected the blocked caller to get released")
+	}
+}
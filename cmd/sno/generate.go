@@ -1,6 +1,7 @@
 package main
 
 import (
+	"io"
 	"os"
 
 	"github.com/muyo/rush/chars"
@@ -14,7 +15,7 @@ func generate(in string) {
 		os.Exit(1)
 	}
 
-	metabyte, snapshot := parseGenerateOpts()
+	metabyte, snapshot, separator := parseGenerateOpts()
 
 	g, err := sno.NewGenerator(snapshot, nil)
 	if err != nil {
@@ -27,23 +28,49 @@ func generate(in string) {
 		ids[i] = g.New(metabyte)
 	}
 
-	buf := make([]byte, sno.SizeEncoded+1)
-	buf[sno.SizeEncoded] = '\n'
+	if err := writeIDs(os.Stdout, ids, separator, !noTrailing); err != nil {
+		os.Exit(1)
+	}
 
-	for i := 0; i < int(c); i++ {
-		enc, _ := ids[i].MarshalText()
-		copy(buf, enc)
-		if _, err := os.Stdout.Write(buf); err != nil {
-			os.Exit(1)
+	os.Exit(0)
+}
+
+// writeIDs writes the encoded representations of the given IDs to w, delimited by sep. If trailing is set,
+// the separator also gets written after the last ID.
+func writeIDs(w io.Writer, ids []sno.ID, sep byte, trailing bool) error {
+	buf := make([]byte, 0, sno.SizeEncoded+1)
+
+	for i := range ids {
+		buf = ids[i].AppendEncoded(buf[:0])
+
+		if trailing || i < len(ids)-1 {
+			buf = append(buf, sep)
+		}
+
+		if _, err := w.Write(buf); err != nil {
+			return err
 		}
 	}
 
-	os.Exit(0)
+	return nil
+}
+
+// separators maps the names accepted by the -sep option to the separators they stand for.
+var separators = map[string]byte{
+	"newline": '\n',
+	"null":    0,
+	"comma":   ',',
+	"space":   ' ',
 }
 
-func parseGenerateOpts() (metabyte byte, snapshot *sno.GeneratorSnapshot) {
+func parseGenerateOpts() (metabyte byte, snapshot *sno.GeneratorSnapshot, separator byte) {
 	var ok bool
 
+	if separator, ok = separators[sep]; !ok {
+		_, _ = os.Stderr.Write([]byte("-sep must be one of: newline, null, comma, space\n"))
+		os.Exit(1)
+	}
+
 	if meta != "" {
 		if metabyte, ok = chars.ParseUint8(meta); !ok {
 			_, _ = os.Stderr.Write([]byte("-meta must be a valid base10 number smaller than 256\n"))
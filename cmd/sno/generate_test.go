@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/muyo/sno"
+)
+
+func TestWriteIDs(t *testing.T) {
+	ids := []sno.ID{
+		{78, 111, 33, 96, 160, 255, 154, 10, 16, 51},
+		{78, 111, 33, 96, 160, 255, 154, 10, 16, 52},
+	}
+
+	for _, c := range []struct {
+		sep      string
+		trailing bool
+		out      string
+	}{
+		{"newline", true, "brpk4q72xwf2m63l\nbrpk4q72xwf2m63m\n"},
+		{"newline", false, "brpk4q72xwf2m63l\nbrpk4q72xwf2m63m"},
+		{"null", true, "brpk4q72xwf2m63l\x00brpk4q72xwf2m63m\x00"},
+		{"null", false, "brpk4q72xwf2m63l\x00brpk4q72xwf2m63m"},
+		{"comma", true, "brpk4q72xwf2m63l,brpk4q72xwf2m63m,"},
+		{"comma", false, "brpk4q72xwf2m63l,brpk4q72xwf2m63m"},
+		{"space", true, "brpk4q72xwf2m63l brpk4q72xwf2m63m "},
+		{"space", false, "brpk4q72xwf2m63l brpk4q72xwf2m63m"},
+	} {
+		var buf bytes.Buffer
+
+		if err := writeIDs(&buf, ids, separators[c.sep], c.trailing); err != nil {
+			t.Fatal(err)
+		}
+
+		if actual, expected := buf.String(), c.out; actual != expected {
+			t.Errorf("%s (trailing: %t): expected [%q], got [%q]", c.sep, c.trailing, expected, actual)
+		}
+	}
+}
@@ -24,9 +24,9 @@ const inspectFmt = `
 `
 
 func inspect(in string) {
-	id, err := sno.FromEncodedString(in)
+	id, err := sno.FromEncodedStringStrict(in)
 	if err != nil {
-		_, _ = os.Stderr.Write([]byte(fmt.Sprintf("Failed to inspect: [%s] does not appear to be a valid sno.\n", in)))
+		_, _ = os.Stderr.Write([]byte(fmt.Sprintf("Failed to inspect: [%s] does not appear to be a valid sno (%v).\n", in, err)))
 		os.Exit(1)
 	}
 
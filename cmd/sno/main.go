@@ -7,22 +7,28 @@ import (
 const (
 	cmdGenerate = "generate"
 	cmdInspect  = "inspect"
+	cmdVerify   = "verify"
 	cmdVersion  = "version"
 	cmdHelp     = "help"
 )
 
 var (
-	meta string
-	part string
+	meta       string
+	part       string
+	sep        string
+	noTrailing bool
 )
 
 func init() {
 	flag.StringVar(&meta, "meta", "", "The metabyte to set on generated IDs, given in decimal (base10)")
 	flag.StringVar(&part, "partition", "", "The partition to set on generated IDs, given in decimal (base10)")
-	flag.Parse()
+	flag.StringVar(&sep, "sep", "newline", "The separator to write after each generated ID: newline, null, comma or space")
+	flag.BoolVar(&noTrailing, "no-trailing", false, "Omit the separator after the last generated ID")
 }
 
 func main() {
+	flag.Parse()
+
 	var (
 		args  = flag.Args()
 		argsN = len(args)
@@ -47,6 +53,8 @@ func main() {
 			generate(args[1])
 		case cmdInspect:
 			inspect(args[1])
+		case cmdVerify:
+			verify(args[1])
 		}
 	}
 
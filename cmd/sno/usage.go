@@ -17,11 +17,19 @@ Commands:
 
               sno inspect <ID>
 
+    verify    Round-trips an ID through its base32, binary, JSON and hex representations,
+              exiting with a non-zero status if any of them does not decode back to the same ID
+
+              sno verify <ID>
+
     generate  Generates one or more IDs
 
               sno generate [options...] [number of IDs to generate]
                   --meta=<decimal>        The metabyte to set on generated IDs, in decimal, max 255
                   --partition=<decimal>   The partition to set on generated IDs, in decimal, max 65535
+                  --sep=<separator>       The separator to write after each ID, one of:
+                                          newline (default), null, comma, space
+                  --no-trailing           Omit the separator after the last ID
 
     version   Displays the version of this program
     help      Displays this information
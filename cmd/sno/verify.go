@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/muyo/sno"
+)
+
+func verify(in string) {
+	if err := verifyID(os.Stdout, in); err != nil {
+		_, _ = os.Stderr.Write([]byte(fmt.Sprintf("Failed to verify: %v.\n", err)))
+		os.Exit(1)
+	}
+
+	os.Exit(0)
+}
+
+// verifyID decodes the given base32-encoded ID, round-trips it through each of its representations
+// and writes them to w, along with the outcome of each round-trip. Returns an error if the input
+// can not be decoded or if any of the representations does not decode back to the same ID.
+func verifyID(w io.Writer, in string) error {
+	id, err := sno.FromEncodedStringStrict(in)
+	if err != nil {
+		return fmt.Errorf("[%s] does not appear to be a valid sno (%v)", in, err)
+	}
+
+	var (
+		enc, _ = id.MarshalText()
+		bin, _ = id.MarshalBinary()
+		js, _  = json.Marshal(id)
+		hx     = hex.EncodeToString(id[:])
+	)
+
+	for _, c := range []struct {
+		name string
+		repr string
+		back func() (sno.ID, error)
+	}{
+		{"Base32", string(enc), func() (sno.ID, error) {
+			if string(enc) != in {
+				return sno.ID{}, fmt.Errorf("does not re-encode to the input")
+			}
+
+			return sno.FromEncodedBytesStrict(enc)
+		}},
+		{"Binary", fmt.Sprint(bin), func() (sno.ID, error) {
+			return sno.FromBinaryBytes(bin)
+		}},
+		{"JSON", string(js), func() (back sno.ID, err error) {
+			return back, json.Unmarshal(js, &back)
+		}},
+		{"Hex", hx, func() (sno.ID, error) {
+			b, err := hex.DecodeString(hx)
+			if err != nil {
+				return sno.ID{}, err
+			}
+
+			return sno.FromBinaryBytes(b)
+		}},
+	} {
+		back, rerr := c.back()
+		if rerr == nil && back != id {
+			rerr = fmt.Errorf("decodes to [%s]", back)
+		}
+
+		status := "ok"
+		if rerr != nil {
+			status = "MISMATCH: " + rerr.Error()
+
+			if err == nil {
+				err = fmt.Errorf("the %s representation of [%s] does not round-trip (%v)", c.name, id, rerr)
+			}
+		}
+
+		if _, werr := fmt.Fprintf(w, "%8s: %-44s %s\n", c.name, c.repr, status); werr != nil {
+			return werr
+		}
+	}
+
+	return err
+}
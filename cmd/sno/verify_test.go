@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// TestVerify_ExitCodes runs the verify command in a subprocess (the test binary itself, calling into main),
+// since it exits the process.
+func TestVerify_ExitCodes(t *testing.T) {
+	if in := os.Getenv("SNO_TEST_VERIFY"); in != "" {
+		os.Args = []string{"sno", cmdVerify, in}
+		main()
+		return
+	}
+
+	for _, c := range []struct {
+		name string
+		in   string
+		code int
+		out  string
+	}{
+		{"valid", "brpk4q72xwf2m63l", 0, "4e6f2160a0ff9a0a1033"},
+		{"invalid-char", "brpk4q72xwf2m63!", 1, ""},
+		{"invalid-size", "brpk4q72xwf2m63", 1, ""},
+		{"upper-case", "BRPK4Q72XWF2M63L", 1, ""},
+	} {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+
+			cmd := exec.Command(os.Args[0], "-test.run=^TestVerify_ExitCodes$")
+			cmd.Env = append(os.Environ(), "SNO_TEST_VERIFY="+c.in)
+			cmd.Stdout = &stdout
+
+			err := cmd.Run()
+
+			code := 0
+			if ee, ok := err.(*exec.ExitError); ok {
+				code = ee.ExitCode()
+			} else if err != nil {
+				t.Fatal(err)
+			}
+
+			if actual, expected := code, c.code; actual != expected {
+				t.Errorf("expected exit code [%d], got [%d]", expected, actual)
+			}
+
+			if c.out != "" && !strings.Contains(stdout.String(), c.out) {
+				t.Errorf("expected output to contain [%s], got [%s]", c.out, stdout.String())
+			}
+		})
+	}
+}
+
+func TestVerifyID(t *testing.T) {
+	var buf bytes.Buffer
+
+	if err := verifyID(&buf, "brpk4q72xwf2m63l"); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if actual, expected := len(lines), 4; actual != expected {
+		t.Fatalf("expected [%d] representations, got [%d]", expected, actual)
+	}
+
+	for _, line := range lines {
+		if !strings.HasSuffix(line, " ok") {
+			t.Errorf("expected round-trip to succeed, got [%s]", line)
+		}
+	}
+}
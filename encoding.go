@@ -0,0 +1,90 @@
+package sno
+
+import "fmt"
+
+// Encoding is a base32 encoding of IDs with a custom alphabet, e.g. Crockford's base32
+// (0123456789ABCDEFGHJKMNPQRSTVWXYZ) for visual consistency with other keys of a datastore.
+//
+// The canonical encoding (as used by ID.String and the codecs of ID) remains unaffected - an Encoding
+// is an explicit opt-in and its representations must be decoded using the same Encoding. Strings encoded
+// with it only sort the same way as the IDs they represent if the alphabet itself is in ascending
+// byte order.
+//
+// An Encoding must be constructed using NewEncoding. It is safe for concurrent use.
+type Encoding struct {
+	enc [32]byte
+	dec [256]byte
+}
+
+// NewEncoding returns a new Encoding using the given alphabet, which must consist of 32 unique bytes.
+// Returns an InvalidAlphabetError otherwise.
+func NewEncoding(alphabet string) (*Encoding, error) {
+	if len(alphabet) != 32 {
+		return nil, &InvalidAlphabetError{Alphabet: alphabet, Msg: fmt.Sprintf("must be 32 bytes long, got %d", len(alphabet))}
+	}
+
+	e := &Encoding{}
+	for i := range e.dec {
+		e.dec[i] = 0xFF
+	}
+
+	for i := 0; i < len(alphabet); i++ {
+		c := alphabet[i]
+		if e.dec[c] != 0xFF {
+			return nil, &InvalidAlphabetError{Alphabet: alphabet, Msg: fmt.Sprintf("duplicate symbol %q", c)}
+		}
+
+		e.enc[i] = c
+		e.dec[c] = byte(i)
+	}
+
+	return e, nil
+}
+
+// Encode returns the base32-encoded representation of the given ID using the alphabet of the Encoding.
+func (e *Encoding) Encode(id ID) string {
+	var dst [SizeEncoded]byte
+
+	// Each of the two 40-bit blocks of the ID maps to 8 symbols.
+	for b := 0; b < 2; b++ {
+		v := uint64(id[b*5])<<32 | uint64(id[b*5+1])<<24 | uint64(id[b*5+2])<<16 | uint64(id[b*5+3])<<8 | uint64(id[b*5+4])
+
+		for i := b*8 + 7; i >= b*8; i-- {
+			dst[i] = e.enc[v&0x1F]
+			v >>= 5
+		}
+	}
+
+	return string(dst[:])
+}
+
+// Decode decodes the given base32-encoded representation of an ID, as returned by Encode.
+//
+// The string must have a length of 16. Returns a InvalidDataSizeError if it does not and an
+// InvalidEncodingError naming the first character which is not within the alphabet of the Encoding.
+func (e *Encoding) Decode(src string) (id ID, err error) {
+	if len(src) != SizeEncoded {
+		return zero, &InvalidDataSizeError{Size: len(src)}
+	}
+
+	for b := 0; b < 2; b++ {
+		var v uint64
+
+		for i := b * 8; i < b*8+8; i++ {
+			d := e.dec[src[i]]
+			if d == 0xFF {
+				return zero, &InvalidEncodingError{Byte: src[i], Index: i}
+			}
+
+			v = v<<5 | uint64(d)
+		}
+
+		id[b*5] = byte(v >> 32)
+		id[b*5+1] = byte(v >> 24)
+		id[b*5+2] = byte(v >> 16)
+		id[b*5+3] = byte(v >> 8)
+		id[b*5+4] = byte(v)
+	}
+
+	return id, nil
+}
@@ -0,0 +1,117 @@
+package sno
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEncoding_RoundTrip(t *testing.T) {
+	ids := []ID{
+		{},
+		{255, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+		{78, 111, 33, 96, 160, 255, 154, 10, 16, 51},
+		New(255),
+	}
+
+	for _, c := range []struct {
+		name     string
+		alphabet string
+	}{
+		{"canonical", "23456789abcdefghijklmnopqrstuvwx"},
+		{"crockford", "0123456789ABCDEFGHJKMNPQRSTVWXYZ"},
+		{"reversed", "xwvutsrqponmlkjihgfedcba98765432"},
+		{"binary", "\x00\x01\x02\x03\x04\x05\x06\x07\x08\x09\x0a\x0b\x0c\x0d\x0e\x0f\xf0\xf1\xf2\xf3\xf4\xf5\xf6\xf7\xf8\xf9\xfa\xfb\xfc\xfd\xfe\xff"},
+	} {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			e, err := NewEncoding(c.alphabet)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, id := range ids {
+				enc := e.Encode(id)
+				if actual, expected := len(enc), SizeEncoded; actual != expected {
+					t.Errorf("expected [%d], got [%d]", expected, actual)
+				}
+
+				actual, err := e.Decode(enc)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if actual != id {
+					t.Errorf("expected [%v], got [%v]", id, actual)
+				}
+			}
+		})
+	}
+}
+
+func TestEncoding_Canonical(t *testing.T) {
+	e, err := NewEncoding("23456789abcdefghijklmnopqrstuvwx")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 64; i++ {
+		id := New(byte(i))
+		if actual, expected := e.Encode(id), id.String(); actual != expected {
+			t.Errorf("expected [%s], got [%s]", expected, actual)
+		}
+	}
+}
+
+func TestEncoding_Crockford(t *testing.T) {
+	e, err := NewEncoding("0123456789ABCDEFGHJKMNPQRSTVWXYZ")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if actual, expected := e.Encode(ID{78, 111, 33, 96, 160, 255, 154, 10, 16, 51}), "9SQJ2R50ZYD0M41K"; actual != expected {
+		t.Errorf("expected [%s], got [%s]", expected, actual)
+	}
+
+	// The alphabet is in ascending byte order, so the strings sort like the IDs.
+	a, b := New(255), New(255)
+	if e.Encode(a) >= e.Encode(b) {
+		t.Errorf("expected [%s] to sort before [%s]", e.Encode(a), e.Encode(b))
+	}
+
+	for _, c := range []struct {
+		in  string
+		err error
+	}{
+		{"9SQJ2R50ZYD0M41U", &InvalidEncodingError{Byte: 'U', Index: 15}},
+		{"9sQJ2R50ZYD0M41K", &InvalidEncodingError{Byte: 's', Index: 1}},
+		{"9SQJ2R50ZYD0M41", &InvalidDataSizeError{Size: 15}},
+	} {
+		if _, err := e.Decode(c.in); !reflect.DeepEqual(err, c.err) {
+			t.Errorf("%s: expected [%v], got [%v]", c.in, c.err, err)
+		}
+	}
+}
+
+func TestNewEncoding_Invalid(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		alphabet string
+		msg      string
+	}{
+		{"duplicate", "0123456789ABCDEFGHJKMNPQRSTVWXY0", `duplicate symbol '0'`},
+		{"short", "0123456789ABCDEFGHJKMNPQRSTVWXY", "must be 32 bytes long, got 31"},
+		{"long", "0123456789ABCDEFGHJKMNPQRSTVWXYZ_", "must be 32 bytes long, got 33"},
+	} {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			e, err := NewEncoding(c.alphabet)
+			if e != nil {
+				t.Errorf("expected [nil], got [%v]", e)
+			}
+
+			if actual, expected := err, (&InvalidAlphabetError{Alphabet: c.alphabet, Msg: c.msg}); !reflect.DeepEqual(actual, expected) {
+				t.Errorf("expected [%v], got [%v]", expected, actual)
+			}
+		})
+	}
+}
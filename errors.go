@@ -1,15 +1,38 @@
 package sno
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 const (
-	errInvalidDataSizeMsg         = "sno: unrecognized data size"
-	errInvalidTypeFmt             = "sno: unrecognized data type: %T"
-	errInvalidSequenceBoundsFmt   = "sno: %s; min: %d, sequence: %d, max: %d, pool: %d"
-	errSequenceBoundsIdenticalMsg = "sno: sequence bounds are identical - need a sequence pool with a capacity of at least 4"
-	errSequenceUnderflowsBound    = "sno: current sequence underflows the given lower bound"
-	errSequencePoolTooSmallMsg    = "sno: generators require a sequence pool with a capacity of at least 4"
-	errPartitionPoolExhaustedMsg  = "sno: process exceeded maximum number of possible defaults-configured generators"
+	errInvalidDataSizeMsg          = "sno: unrecognized data size"
+	errInvalidTypeFmt              = "sno: unrecognized data type: %T"
+	errInvalidSequenceBoundsFmt    = "sno: %s; min: %d, sequence: %d, max: %d, pool: %d"
+	errSequenceBoundsIdenticalMsg  = "sno: sequence bounds are identical - need a sequence pool with a capacity of at least 4"
+	errSequenceUnderflowsBound     = "sno: current sequence underflows the given lower bound"
+	errSequencePoolTooSmallMsg     = "sno: generators require a sequence pool with a capacity of at least 4"
+	errSequenceJitterTooLargeMsg   = "sno: sequence jitter must leave room for a sequence pool with a capacity of at least 4"
+	errSequenceLeaseOutOfBoundsMsg = "sno: leased sequence band falls outside of the sequence bounds"
+	errPartitionPoolExhaustedMsg   = "sno: process exceeded maximum number of possible defaults-configured generators"
+	errBatchDecodeFmt              = "sno: failed to decode element at index %d: %s"
+	errSequenceRangesOverlapFmt    = "sno: sequence ranges overlap; [%d, %d] and [%d, %d]"
+	errTimestampRangeFmt           = "sno: time %s is out of the range of embeddable timestamps; units: %d, min: %d, max: %d"
+	errReservedMetaFmt             = "sno: metabyte %d is reserved"
+	errInvalidDecimalFmt           = "sno: invalid decimal representation of an ID: %q"
+	errInvalidBase62Fmt            = "sno: invalid base62 representation of an ID: %q"
+	errInvalidTenantFmt            = "sno: tenant %d exceeds the max tenant of %d"
+	errSequencePoolExhaustedFmt    = "sno: sequence pool exhausted within the current timeframe; sequence: %d, max: %d"
+	errInvalidDefaultPartitionFmt  = "sno: invalid DefaultPartition %q - must be a uint16 in base 10"
+	errInvalidEncodingFmt          = "sno: invalid character %q at index %d - not within the encoding alphabet"
+	errInvalidAlphabetFmt          = "sno: invalid encoding alphabet %q - %s"
+	errInvalidBatchSizeFmt         = "sno: batch size %d exceeds the max of %d"
+	errInvalidMinFirstIDFmt        = "sno: MinFirstID %s lies %s ahead of the current time - not plausibly recent"
+	errInvalidSchemaVersionFmt     = "sno: schema version %d and payload %d do not fit a metabyte with %d version bits"
+	errInvalidSchemaVersionBitsFmt = "sno: %d schema version bits do not fit a metabyte"
+	errInvalidEpochFmt             = "sno: epoch %d is out of range - the current time must be embeddable relative to it"
+	errInvalidSizeFmt              = "sno: %s requires a positive size, got %d"
+	errShardedOptionFmt            = "sno: sharded generators do not support %s - the shards can not share it"
 )
 
 // InvalidDataSizeError gets returned when attempting to unmarshal or decode an ID from data that
@@ -46,6 +69,42 @@ func (e *InvalidSequenceBoundsError) Error() string {
 	return fmt.Sprintf(errInvalidSequenceBoundsFmt, e.Msg, e.Min, e.Cur, e.Max, e.Max-e.Min+1)
 }
 
+// TimestampRangeError gets returned when a time can not be embedded in an ID as its timestamp,
+// because it falls before our epoch or after MaxTimestamp.
+//
+// Units is the timestamp the time translates to in sno time units, which is out of the valid
+// range of [Min, Max].
+type TimestampRangeError struct {
+	Time  time.Time
+	Units int64
+	Min   int64
+	Max   int64
+}
+
+func (e *TimestampRangeError) Error() string {
+	return fmt.Sprintf(errTimestampRangeFmt, e.Time.UTC().Format(time.RFC3339Nano), e.Units, e.Min, e.Max)
+}
+
+func timestampRangeError(t time.Time, units int64) *TimestampRangeError {
+	return &TimestampRangeError{
+		Time:  t,
+		Units: units,
+		Min:   0,
+		Max:   MaxTimestamp,
+	}
+}
+
+// SequenceRangesOverlapError gets returned by ValidatePartitioning when two of the sequence ranges
+// given to it overlap. The ranges are reported with their bounds in ascending order.
+type SequenceRangesOverlapError struct {
+	A [2]uint16
+	B [2]uint16
+}
+
+func (e *SequenceRangesOverlapError) Error() string {
+	return fmt.Sprintf(errSequenceRangesOverlapFmt, e.A[0], e.A[1], e.B[0], e.B[1])
+}
+
 // PartitionPoolExhaustedError gets returned when attempting to create more than MaxPartition (65535)
 // Generators using the default configuration (eg. without snapshots).
 //
@@ -53,3 +112,189 @@ func (e *InvalidSequenceBoundsError) Error() string {
 type PartitionPoolExhaustedError struct{}
 
 func (e *PartitionPoolExhaustedError) Error() string { return errPartitionPoolExhaustedMsg }
+
+// BatchDecodeError gets returned when decoding one of the elements of a batch fails, e.g. in DecodeAll.
+//
+// Index is the index of the offending element within the batch and Err the underlying error.
+type BatchDecodeError struct {
+	Index int
+	Err   error
+}
+
+func (e *BatchDecodeError) Error() string {
+	return fmt.Sprintf(errBatchDecodeFmt, e.Index, e.Err)
+}
+
+// Unwrap returns the underlying error of the element that failed to decode.
+func (e *BatchDecodeError) Unwrap() error { return e.Err }
+
+// ReservedMetaError gets returned (or panicked with) when attempting to generate an ID with a metabyte
+// which is reserved in the Generator (see GeneratorSnapshot.ReservedMeta).
+type ReservedMetaError struct {
+	Meta byte
+}
+
+func (e *ReservedMetaError) Error() string {
+	return fmt.Sprintf(errReservedMetaFmt, e.Meta)
+}
+
+// InvalidBatchSizeError gets panicked with when requesting a batch larger than the method supports
+// (see Generator.NewBatchIndexed).
+type InvalidBatchSizeError struct {
+	Size int
+	Max  int
+}
+
+func (e *InvalidBatchSizeError) Error() string {
+	return fmt.Sprintf(errInvalidBatchSizeFmt, e.Size, e.Max)
+}
+
+// InvalidDecimalError gets returned by FromDecimal when the given string is not a valid base-10
+// representation of an ID.
+type InvalidDecimalError struct {
+	Input string
+}
+
+func (e *InvalidDecimalError) Error() string {
+	return fmt.Sprintf(errInvalidDecimalFmt, e.Input)
+}
+
+// InvalidBase62Error gets returned by FromBase62 when the given string is not a valid base-62
+// representation of an ID.
+type InvalidBase62Error struct {
+	Input string
+}
+
+func (e *InvalidBase62Error) Error() string {
+	return fmt.Sprintf(errInvalidBase62Fmt, e.Input)
+}
+
+// InvalidTenantError gets returned by Generator.NewForTenant when the given tenant id exceeds MaxTenant.
+type InvalidTenantError struct {
+	Tenant uint32
+}
+
+func (e *InvalidTenantError) Error() string {
+	return fmt.Sprintf(errInvalidTenantFmt, e.Tenant, MaxTenant)
+}
+
+// SequencePoolExhaustedError gets returned by Generator.TryNew when the sequence pool of the Generator
+// (or its leased band, see GeneratorOptions.Leaser) is exhausted within the current timeframe - and by
+// the other generation methods which return errors, if the Generator fails on overflows (see
+// GeneratorSnapshot.FailOnOverflow).
+//
+// Sequence is the sequence that overflowed and Max the upper bound of the pool, so Sequence-Max
+// tells how deep the overflow was.
+type SequencePoolExhaustedError struct {
+	Sequence uint32
+	Max      uint16
+}
+
+func (e *SequencePoolExhaustedError) Error() string {
+	return fmt.Sprintf(errSequencePoolExhaustedFmt, e.Sequence, e.Max)
+}
+
+// InvalidDefaultPartitionError gets panicked with during package initialization when DefaultPartition
+// got set to a value which is not a valid Partition.
+type InvalidDefaultPartitionError struct {
+	Value string
+}
+
+func (e *InvalidDefaultPartitionError) Error() string {
+	return fmt.Sprintf(errInvalidDefaultPartitionFmt, e.Value)
+}
+
+// InvalidEncodingError gets returned by FromEncodedStringStrict and FromEncodedBytesStrict when their
+// input contains a character which is not within the alphabet of the canonical encoding.
+//
+// Byte is the first offending character and Index its position within the input.
+type InvalidEncodingError struct {
+	Byte  byte
+	Index int
+}
+
+func (e *InvalidEncodingError) Error() string {
+	return fmt.Sprintf(errInvalidEncodingFmt, e.Byte, e.Index)
+}
+
+// InvalidAlphabetError gets returned by NewEncoding when the given alphabet does not consist
+// of exactly 32 unique bytes.
+type InvalidAlphabetError struct {
+	Alphabet string
+	Msg      string
+}
+
+func (e *InvalidAlphabetError) Error() string {
+	return fmt.Sprintf(errInvalidAlphabetFmt, e.Alphabet, e.Msg)
+}
+
+// InvalidEpochError gets returned by NewGenerator when the epoch of the given snapshot is in the future
+// or so far in the past that the current time can not be embedded relative to it.
+type InvalidEpochError struct {
+	Epoch int64
+}
+
+func (e *InvalidEpochError) Error() string {
+	return fmt.Sprintf(errInvalidEpochFmt, e.Epoch)
+}
+
+// InvalidMinFirstIDError gets returned by NewGenerator when the GeneratorSnapshot.MinFirstID of the given
+// snapshot lies too far ahead of the current time to be plausible. Lead is how far ahead it lies.
+type InvalidMinFirstIDError struct {
+	ID   ID
+	Lead time.Duration
+}
+
+func (e *InvalidMinFirstIDError) Error() string {
+	return fmt.Sprintf(errInvalidMinFirstIDFmt, e.ID, e.Lead)
+}
+
+// InvalidSchemaVersionError gets returned by Generator.NewVersioned and ID.WithSchemaVersion when the given
+// schema version or payload do not fit into their share of the metabyte, as split by Bits
+// (see GeneratorSnapshot.SchemaVersionBits).
+type InvalidSchemaVersionError struct {
+	Version uint8
+	Payload uint8
+	Bits    uint8
+}
+
+func (e *InvalidSchemaVersionError) Error() string {
+	return fmt.Sprintf(errInvalidSchemaVersionFmt, e.Version, e.Payload, e.Bits)
+}
+
+// InvalidSchemaVersionBitsError gets returned by NewGenerator and ID.WithSchemaVersion (and panicked
+// with by ID.SchemaSplit) when given a number of schema version bits which exceeds the 8 bits of the metabyte.
+type InvalidSchemaVersionBitsError struct {
+	Bits uint8
+}
+
+func (e *InvalidSchemaVersionBitsError) Error() string {
+	return fmt.Sprintf(errInvalidSchemaVersionBitsFmt, e.Bits)
+}
+
+// ShardedOptionError gets returned by NewShardedGenerator when given an option its shards can not share.
+// Option is the name of the option, one of:
+//	GeneratorOptions.Leaser: the bands it hands out span the entire sequence pool, not the slice
+//		of it owned by each shard.
+//	GeneratorOptions.LoadFloor, GeneratorOptions.SaveFloor: each shard would load and save the floor
+//		on its own, so the saves of the shards would race each other.
+//	GeneratorSnapshot.MinFirstID: each shard would treat it as a floor of its own, with the restart
+//		guarantee resting on only one of the shards having emitted the ID.
+type ShardedOptionError struct {
+	Option string
+}
+
+func (e *ShardedOptionError) Error() string {
+	return fmt.Sprintf(errShardedOptionFmt, e.Option)
+}
+
+// InvalidSizeError gets panicked with when a size (or count) which must be positive is not, e.g. when
+// given to ID.Shard, NewArena, NewRing or BenchmarkCodec. Func is the name of the function it got passed to.
+type InvalidSizeError struct {
+	Func string
+	Size int
+}
+
+func (e *InvalidSizeError) Error() string {
+	return fmt.Sprintf(errInvalidSizeFmt, e.Func, e.Size)
+}
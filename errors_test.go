@@ -0,0 +1,31 @@
+package sno
+
+import (
+	"testing"
+	"time"
+)
+
+func TestErrors_TimestampRange(t *testing.T) {
+	var (
+		tn  = time.Date(2009, 12, 31, 23, 59, 59, 996000000, time.UTC)
+		err = timestampRangeError(tn, -1)
+	)
+
+	if actual, expected := err.Max, int64(MaxTimestamp); actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	expected := "sno: time 2009-12-31T23:59:59.996Z is out of the range of embeddable timestamps; units: -1, min: 0, max: 549755813887"
+	if actual := err.Error(); actual != expected {
+		t.Errorf("expected [%s], got [%s]", expected, actual)
+	}
+}
+
+func TestErrors_InvalidEncoding(t *testing.T) {
+	err := &InvalidEncodingError{Byte: '!', Index: 3}
+
+	expected := `sno: invalid character '!' at index 3 - not within the encoding alphabet`
+	if actual := err.Error(); actual != expected {
+		t.Errorf("expected [%s], got [%s]", expected, actual)
+	}
+}
@@ -0,0 +1,51 @@
+package sno
+
+import (
+	"io"
+	"time"
+)
+
+// Feed generates IDs using the given Generator and writes them to w at approximately the given rate
+// (per second) for the given duration - e.g. to load-test systems ingesting IDs. Returns the number
+// of IDs written, which is at most rate * dur.
+//
+// The IDs get written in their canonical encoding, each followed by a newline, with one call to Write
+// per ID - writers for which that is expensive (e.g. files or sockets) should be buffered (see bufio).
+//
+// The rate is approximate: IDs are due at evenly spaced points in time and Feed sleeps until each is due,
+// but sleeps tend to overshoot - in which case Feed catches up by writing the IDs which became due in the
+// meantime in a burst. Feed stops once dur has elapsed, so a writer too slow to keep up results in fewer
+// IDs. So does a rate exceeding the capacity of the Generator, as New waits for the next timeframe
+// whenever the sequence pool is exhausted.
+//
+// Returns the first error w returned, along with the number of IDs written up to that point.
+func Feed(w io.Writer, g *Generator, meta byte, rate int, dur time.Duration) (n int, err error) {
+	if rate <= 0 || dur <= 0 {
+		return 0, nil
+	}
+
+	var (
+		buf      = make([]byte, 0, SizeEncoded+1)
+		interval = float64(time.Second) / float64(rate)
+		start    = time.Now()
+	)
+
+	for {
+		due, elapsed := time.Duration(float64(n)*interval), time.Since(start)
+		if due >= dur || elapsed >= dur {
+			return n, nil
+		}
+
+		if wait := due - elapsed; wait > 0 {
+			time.Sleep(wait)
+		}
+
+		buf = append(g.New(meta).AppendEncoded(buf[:0]), '\n')
+
+		if _, err = w.Write(buf); err != nil {
+			return n, err
+		}
+
+		n++
+	}
+}
@@ -0,0 +1,94 @@
+package sno
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFeed(t *testing.T) {
+	g, err := NewGenerator(&GeneratorSnapshot{
+		Partition: Partition{'F', 'D'},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		buf   bytes.Buffer
+		start = time.Now()
+	)
+
+	n, err := Feed(&buf, g, 255, 1000, 50*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The last of the 50 IDs is due at 49ms. Sleeps may overshoot, so fewer may make it in time.
+	if elapsed := time.Since(start); elapsed < 49*time.Millisecond {
+		t.Errorf("expected Feed to take at least [49ms], took [%s]", elapsed)
+	}
+
+	if n < 10 || n > 50 {
+		t.Errorf("expected within [10, 50] IDs, got [%d]", n)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if actual, expected := len(lines), n; actual != expected {
+		t.Fatalf("expected [%d] lines, got [%d]", expected, actual)
+	}
+
+	var prev ID
+	for i, line := range lines {
+		id, err := FromEncodedStringStrict(line)
+		if err != nil {
+			t.Fatalf("%d: %v", i, err)
+		}
+
+		if id.Compare(prev) <= 0 {
+			t.Errorf("%d: expected [%s] to sort after [%s]", i, id, prev)
+		}
+
+		prev = id
+	}
+}
+
+type failingWriter struct {
+	n int
+}
+
+var errFailingWriter = errors.New("write failed")
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.n == 0 {
+		return 0, errFailingWriter
+	}
+
+	w.n--
+
+	return len(p), nil
+}
+
+func TestFeed_WriteError(t *testing.T) {
+	g, err := NewGenerator(&GeneratorSnapshot{
+		Partition: Partition{'F', 'D'},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := Feed(&failingWriter{n: 3}, g, 255, 1e6, time.Second)
+	if err != errFailingWriter {
+		t.Errorf("expected [%v], got [%v]", errFailingWriter, err)
+	}
+
+	if actual, expected := n, 3; actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	if n, err := Feed(&failingWriter{}, g, 255, 0, time.Second); n != 0 || err != nil {
+		t.Errorf("expected [0, nil], got [%d, %v]", n, err)
+	}
+}
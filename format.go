@@ -0,0 +1,42 @@
+package sno
+
+// Format is a representation of an ID.
+type Format uint8
+
+const (
+	// FormatUnknown denotes data which is not a recognized representation of an ID.
+	FormatUnknown Format = iota
+
+	// FormatBinary denotes the binary representation of an ID (SizeBinary bytes).
+	FormatBinary
+
+	// FormatEncoded denotes the canonical base32-encoded representation of an ID (SizeEncoded bytes).
+	FormatEncoded
+)
+
+// String implements fmt.Stringer by returning the name of the Format.
+func (f Format) String() string {
+	switch f {
+	case FormatBinary:
+		return "binary"
+	case FormatEncoded:
+		return "encoded"
+	default:
+		return "unknown"
+	}
+}
+
+// DetectFormat returns the Format the given data is represented in, based on its length.
+//
+// Detection does not validate the contents of the data - e.g. any 16 bytes are reported
+// as FormatEncoded.
+func DetectFormat(b []byte) Format {
+	switch len(b) {
+	case SizeBinary:
+		return FormatBinary
+	case SizeEncoded:
+		return FormatEncoded
+	default:
+		return FormatUnknown
+	}
+}
@@ -0,0 +1,39 @@
+package sno
+
+import "testing"
+
+func TestFormat_DetectFormat(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		in   []byte
+		out  Format
+	}{
+		{"nil", nil, FormatUnknown},
+		{"empty", []byte{}, FormatUnknown},
+		{"binary", make([]byte, SizeBinary), FormatBinary},
+		{"encoded", []byte("brpk4q72xwf2m63l"), FormatEncoded},
+		{"short", make([]byte, SizeBinary-1), FormatUnknown},
+		{"between", make([]byte, SizeBinary+1), FormatUnknown},
+		{"long", make([]byte, SizeEncoded+1), FormatUnknown},
+	} {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			if actual, expected := DetectFormat(c.in), c.out; actual != expected {
+				t.Errorf("expected [%s], got [%s]", expected, actual)
+			}
+		})
+	}
+}
+
+func TestFormat_String(t *testing.T) {
+	for f, expected := range map[Format]string{
+		FormatUnknown: "unknown",
+		FormatBinary:  "binary",
+		FormatEncoded: "encoded",
+		Format(255):   "unknown",
+	} {
+		if actual := f.String(); actual != expected {
+			t.Errorf("expected [%s], got [%s]", expected, actual)
+		}
+	}
+}
@@ -2,7 +2,12 @@
 package sno
 
 import (
+	"context"
+	"crypto/rand"
 	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -13,6 +18,10 @@ import (
 // Snapshots serve both as configuration and a means of restoring generators across restarts,
 // to ensure newly generated IDs don't overwrite IDs generated before going offline.
 type GeneratorSnapshot struct {
+	// Name (optional) labels the Generator for diagnostic purposes, e.g. to tell apart which
+	// of several Generators the SequenceOverflowNotifications and GeneratorStats come from.
+	Name string `json:"name"`
+
 	// The Partition the generator is scoped to. A zero value ({0, 0}) is valid and will be used.
 	Partition Partition `json:"partition"`
 
@@ -25,15 +34,221 @@ type GeneratorSnapshot struct {
 	// but not underflow SequenceMin.
 	Sequence uint32 `json:"sequence"`
 
-	Now      int64  `json:"now"`      // Wall time the snapshot was taken at in sno time units and in our epoch.
+	Now      int64  `json:"now"`      // Wall time the snapshot was taken at in sno time units and in the Generator's epoch.
 	WallHi   int64  `json:"wallHi"`   //
 	WallSafe int64  `json:"wallSafe"` //
 	Drifts   uint32 `json:"drifts"`   // Count of wall clock regressions the generator tick-tocked at.
+
+	// LogicalClock makes the Generator maintain its own logical clock, meant for environments
+	// where the wall clock is unreliable (e.g. containers with paused and resumed time).
+	//
+	// The logical clock follows the wall clock whenever the latter is ahead, but never regresses
+	// along with it (meaning no tick-tocks happen) and - instead of blocking callers until the wall clock
+	// progresses - it advances by itself by one time unit whenever the sequence pool gets exhausted.
+	//
+	// Generation is therefore guaranteed to be monotonic and non-blocking, at the cost of the timestamps
+	// diverging from wall time: during sustained overflows and after wall clock regressions IDs get
+	// timestamps ahead of the wall clock until it catches up.
+	LogicalClock bool `json:"logicalClock"`
+
+	// OneShotPerTimeframe makes the Generator emit at most one distinct ID per timeframe (for a given
+	// metabyte) - all calls to New() within the same timeframe return the same ID, with its sequence
+	// fixed at SequenceMin. IDs become a pure function of time, partition and metabyte, e.g. for use
+	// as idempotent, time-bucketed keys of low-rate producers.
+	//
+	// This intentionally sacrifices uniqueness within a timeframe and, in turn, never overflows.
+	// Has no effect on Generators with a Leaser (see GeneratorOptions.Leaser).
+	OneShotPerTimeframe bool `json:"oneShotPerTimeframe"`
+
+	// FailOnOverflow makes the generation methods which return errors (NewContext, NewForTenant and
+	// NewVersioned) fail fast instead of blocking when the sequence pool gets exhausted within a timeframe -
+	// returning a SequencePoolExhaustedError carrying the overflowing sequence and SequenceMax, like TryNew
+	// always does - e.g. for latency-sensitive services implementing their own backpressure.
+	//
+	// New and the other generation methods which can not return errors are not affected and keep blocking
+	// on overflows, as the zero ID they would have to return instead is easily mistaken for a real one.
+	// Has no effect with a LogicalClock, which never blocks callers.
+	FailOnOverflow bool `json:"failOnOverflow"`
+
+	// Checksum makes the Generator use the metabyte of IDs as a checksum (CRC-8) over their other 9 bytes,
+	// so that corruption of any of their components can be detected via ID.VerifyChecksum - without
+	// changing the canonical form of IDs.
+	//
+	// The checksum and user-specified metabytes are mutually exclusive: the metabyte given to (or derived
+	// by) any generation method gets ignored in favor of the checksum.
+	Checksum bool `json:"checksum"`
+
+	// SchemaVersionBits (optional) is the number of high bits of the metabyte which hold the schema version
+	// of IDs generated via NewVersioned, in range [1, 8]. Defaults to DefaultSchemaVersionBits when 0.
+	//
+	// The split determines how the metabytes of those IDs decode, so it must not change over the lifetime
+	// of their schema - see ID.WithSchemaVersion to migrate IDs to a new split instead.
+	SchemaVersionBits uint8 `json:"schemaVersionBits,omitempty"`
+
+	// Jitter (optional) makes the Generator start each timeframe at a random sequence within
+	// [SequenceMin, SequenceMin+Jitter] instead of at SequenceMin, as a defense-in-depth for clusters
+	// where the assignment of partitions is best-effort: two Generators which accidentally share
+	// a Partition are then less likely to generate identical IDs, as long as they do not generate
+	// many IDs per timeframe.
+	//
+	// This reduces the capacity per timeframe by up to Jitter IDs - the bounds must leave room
+	// for at least 4 IDs past the jitter. It is not a substitute for proper partition management.
+	// Generators with a Leaser (see GeneratorOptions.Leaser) and in OneShotPerTimeframe mode do not apply jitter.
+	Jitter uint16 `json:"jitter"`
+
+	// MinGap (optional) makes the Generator enforce a minimum wall time gap between successive IDs
+	// generated using its own clock, effectively limiting it to at most one ID per gap - e.g. for
+	// pacing-sensitive downstreams where each ID triggers an expensive operation. Calls made sooner
+	// than MinGap after the previous one sleep until the gap has elapsed.
+	//
+	// This serializes generation: callers queue up behind a lock and get paced one at a time, so
+	// concurrent callers wait for each other in addition to the gap. IDs generated via NewWithTime
+	// and its variants are not paced.
+	MinGap time.Duration `json:"minGap"`
+
+	// Epoch (optional) is the origin of the timestamps the Generator embeds, in seconds since the Unix epoch,
+	// e.g. 2020-01-01 for systems which went online in 2020, so as to not waste the range of timestamps
+	// (and push their exhaustion past 2079). Defaults to the package's Epoch (2010-01-01) when 0.
+	//
+	// The epoch is not stored in IDs. ID.Time and ID.Timestamp always assume the package's Epoch, so times
+	// of IDs generated with a custom epoch must be read back using ID.TimeWithEpoch (see Generator.Epoch).
+	// Likewise, IDs generated by Generators with different epochs are not comparable with each other.
+	//
+	// The current time must be representable relative to the epoch - NewGenerator returns an
+	// InvalidEpochError for epochs in the future or so far in the past that the current time lies
+	// beyond MaxTimestamp.
+	Epoch int64 `json:"epoch"`
+
+	// ReservedMeta (optional) is the set of metabytes reserved for other uses (e.g. 255 for "system"
+	// records), which the Generator refuses to generate IDs with - enforcing a metabyte allocation
+	// policy at the Generator boundary.
+	//
+	// Enforcement depends on the method: TryNew and NewWithUnixNano return a ReservedMetaError,
+	// while New, NewForPartition, NewWithGap and NewWithTime - which can not return errors - panic
+	// with one. Metabytes derived by the Generator itself (NewWithTimeByte, NewFromContent and
+	// checksums) are not subject to the reservation.
+	ReservedMeta MetaSet `json:"reservedMeta"`
+
+	// MinFirstID (optional) is an ID the first ID emitted by the Generator must sort strictly after, e.g. the
+	// last ID emitted by a previous instance whose full state got lost. The Generator treats its timestamp
+	// as a floor (see GeneratorOptions.LoadFloor), so it must have been generated in the same epoch.
+	//
+	// Since the Generator can not emit IDs before the wall clock catches up with the floor, NewGenerator
+	// returns an InvalidMinFirstIDError if the ID lies more than a minute ahead of the current time - which
+	// points at a corrupted ID or one from another epoch rather than mere clock skew between hosts.
+	//
+	// MinFirstID is not included in snapshots returned by Generator.Snapshot().
+	MinFirstID *ID `json:"minFirstID,omitempty"`
+}
+
+// GeneratorOptions holds the hooks a Generator calls into, as given to NewGeneratorWithOptions. Unlike
+// the GeneratorSnapshot, they are not part of the state of a Generator - they are neither included
+// in snapshots returned by Generator.Snapshot() nor persisted along with them, and must be given again
+// whenever a Generator gets restored.
+type GeneratorOptions struct {
+	// LoadFloor and SaveFloor (optional) let the Generator persist a floor timestamp, guaranteeing that IDs
+	// never go backwards across restarts - even after a crash which left no snapshot behind and even
+	// if the wall clock got reset in the meantime.
+	//
+	// LoadFloor gets called once, when the Generator gets constructed. The Generator then never emits IDs
+	// with timestamps at or below the floor it returned. If the wall clock is behind the floor, New() blocks
+	// until it catches up (unless GeneratorSnapshot.LogicalClock is set, in which case the logical clock
+	// starts right above the floor).
+	//
+	// SaveFloor gets called with the high-water mark (in sno time units and in the Generator's epoch) whenever
+	// the Generator advances to a new timeframe and does so synchronously - *before* any ID within that
+	// timeframe gets handed out. Calls are serialized and the values given are strictly increasing.
+	//
+	// This is where the tradeoff lies: for the guarantee to hold, the value must be durable by the time
+	// SaveFloor returns, but the Generator advances up to 250 times per second and every caller
+	// entering a new timeframe waits for the save to complete. Implementations may instead persist
+	// a value ahead of the one given (e.g. rounded up to the next full second) and skip writes for
+	// as long as the values given remain below what has already been persisted - at the cost of a
+	// restarted Generator having to wait longer for the wall clock to catch up with its floor.
+	//
+	// The floor only guards against restarts. Within a running Generator, wall clock regressions
+	// are handled by the tick-tock mechanism as usual (or by the logical clock, if set).
+	LoadFloor func() uint64
+	SaveFloor func(wall uint64)
+
+	// Clock (optional) is the time source of the Generator, e.g. a hybrid logical clock or a mocked clock
+	// in integration tests. It must return the current time in sno time units (TimeUnit) since the epoch
+	// of the Generator (see GeneratorSnapshot.Epoch) - when not set, the Generator follows the wall clock
+	// of the OS.
+	//
+	// The Generator relies on the clock to progress: it handles regressions of the clock the same way it
+	// handles regressions of the wall clock, but callers blocked due to an overflow or a repeated regression
+	// only get released once the clock moves past the timeframe they are waiting on.
+	Clock func() uint64
+
+	// Leaser (optional) makes the Generator draw its sequences from bands leased from an external
+	// store, so that multiple Generators (e.g. in separate processes) can share one Partition - each within
+	// bands disjoint from those of the others. GeneratorSnapshot.SequenceMin and SequenceMax then bound
+	// the entire pool the bands get leased from.
+	//
+	// The initial lease is acquired during construction (and NewGeneratorWithOptions fails if it does).
+	// Afterwards a new lease gets requested whenever the current band gets exhausted within a single timeframe.
+	// That round-trip happens synchronously within the call to New() which exhausted the band, while all
+	// other callers wait, so the latency of the store directly adds to the latency of generation
+	// on each exhaustion - bands sized to last an entire timeframe under the expected load keep those
+	// round-trips rare. Should a lease fail, the Generator falls back to waiting for the next timeframe
+	// and reuses its current band (or advances the logical clock, if GeneratorSnapshot.LogicalClock
+	// is set).
+	//
+	// Generation is serialized under a lock in this mode, so it is also slower than the lock-free
+	// default even when no round-trips happen. Len() and Sequence() are relative to SequenceMin,
+	// not to the current band.
+	Leaser SequenceLeaser
+
+	// OverflowFunc (optional) gets called with a SequenceOverflowNotification on each tick while
+	// the Generator is overflowing (and once more when it declogs). See NewGeneratorWithCallback.
+	OverflowFunc func(SequenceOverflowNotification)
+}
+
+// MetaSet is a set of metabytes - e.g. those reserved for other uses (see GeneratorSnapshot.ReservedMeta).
+// Its zero value is the empty set. It can be written as a composite literal:
+//	MetaSet{254: true, 255: true}
+//
+// In JSON, it is represented as an object with the metabytes in the set as its keys, e.g. {"255":true}.
+type MetaSet [256]bool
+
+// MarshalJSON implements encoding/json.Marshaler. The empty set is represented as null.
+func (s MetaSet) MarshalJSON() ([]byte, error) {
+	var m map[byte]bool
+	for meta, ok := range s {
+		if !ok {
+			continue
+		}
+
+		if m == nil {
+			m = make(map[byte]bool)
+		}
+
+		m[byte(meta)] = true
+	}
+
+	return json.Marshal(m)
+}
+
+// UnmarshalJSON implements encoding/json.Unmarshaler. Metabytes mapped to false are not in the set.
+func (s *MetaSet) UnmarshalJSON(data []byte) error {
+	var m map[byte]bool
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+
+	*s = MetaSet{}
+	for meta, ok := range m {
+		s[meta] = ok
+	}
+
+	return nil
 }
 
 // SequenceOverflowNotification contains information pertaining to the current state of a Generator
 // while it is overflowing.
 type SequenceOverflowNotification struct {
+	Name  string    // Name of the Generator (see GeneratorSnapshot.Name).
 	Now   time.Time // Time of tick.
 	Count uint32    // Number of currently overflowing generation calls.
 	Ticks uint32    // Total count of ticks while dealing with the *current* overflow.
@@ -47,6 +262,7 @@ type SequenceOverflowNotification struct {
 //
 // A Generator must not be copied after first use.
 type Generator struct {
+	name      string // Immutable.
 	partition uint32 // Immutable.
 
 	drifts     uint32     // Uses the LSB for the tick-tock and serves as a counter.
@@ -54,43 +270,203 @@ type Generator struct {
 	wallSafe   uint64     // Atomic.
 	regression sync.Mutex // Regression branch lock.
 
+	regressionSleeps uint64 // Atomic. See GeneratorStats.RegressionSleeps.
+	regressionSlept  uint64 // Atomic. See GeneratorStats.RegressionSleepTime.
+
 	seq       uint32 // Atomic.
+	seqFirst  uint32 // Atomic. Sequence the current timeframe started at - above seqMin when jittered.
 	seqMin    uint32 // Immutable.
 	seqMax    uint32 // Immutable.
 	seqStatic uint32 // Atomic. See NewWithTime. Not included in snapshots (does not get restored).
 
+	logical bool // Immutable. See GeneratorSnapshot.LogicalClock.
+	oneShot bool // Immutable. See GeneratorSnapshot.OneShotPerTimeframe.
+	sum     bool // Immutable. See GeneratorSnapshot.Checksum.
+
+	schemaBits uint8 // Immutable. See GeneratorSnapshot.SchemaVersionBits.
+
+	failFast bool // Immutable. See GeneratorSnapshot.FailOnOverflow.
+
+	jitter      uint32 // Immutable. See GeneratorSnapshot.Jitter.
+	jitterState uint64 // Atomic. Weyl sequence fed to the jitter mixer.
+
+	gap     time.Duration // Immutable. See GeneratorSnapshot.MinGap.
+	gapMu   sync.Mutex    // Serializes pacing.
+	gapLast time.Time     // Behind gapMu. Time the most recent paced call got released at.
+
+	spacedMu   sync.Mutex // Serializes NewSpaced.
+	spacedLast uint64     // Behind spacedMu. Timeframe of the most recent ID generated via NewSpaced.
+
+	reserved *MetaSet // Immutable. Nil when no metabytes are reserved. See GeneratorSnapshot.ReservedMeta.
+
+	clock func() uint64 // Immutable. Nil when following the wall clock. See GeneratorOptions.Clock.
+
+	epoch    int64  // Immutable. Unix seconds. See GeneratorSnapshot.Epoch.
+	epochOff uint64 // Immutable. Offset of the epoch to ours in time units (wraps around for earlier epochs).
+
+	floorSave func(uint64) // Immutable. See GeneratorOptions.SaveFloor.
+	floorMu   sync.Mutex   // Serializes calls to floorSave.
+	floor     uint64       // Behind floorMu. The highest floor saved.
+
+	leaser    SequenceLeaser // Immutable. See GeneratorOptions.Leaser.
+	leaseMu   sync.Mutex     // Serializes generation when leasing.
+	leaseMin  uint32         // Behind leaseMu. Lower bound of the current band.
+	leaseMax  uint32         // Behind leaseMu. Upper bound of the current band.
+	leaseLen  uint32         // Behind leaseMu. Count of IDs generated in the current timeframe.
+	leaseWall uint64         // Behind leaseMu. Timeframe of the most recent failed lease attempt.
+
 	seqOverflowCond   *sync.Cond
 	seqOverflowTicker *time.Ticker
-	seqOverflowCount  uint32 // Behind seqOverflowCond lock.
+	seqOverflowCount  uint32        // Behind seqOverflowCond lock.
+	seqOverflowStats  OverflowStats // Behind seqOverflowCond lock.
 	seqOverflowChan   chan<- *SequenceOverflowNotification
+	seqOverflowFunc   func(SequenceOverflowNotification) // Immutable. See GeneratorOptions.OverflowFunc.
+
+	frames [statsWindow]uint64 // Atomic. See Stats.
 }
 
 // NewGenerator returns a new generator based on the optional Snapshot.
 func NewGenerator(snapshot *GeneratorSnapshot, c chan<- *SequenceOverflowNotification) (*Generator, error) {
+	return NewGeneratorWithOptions(snapshot, nil, c)
+}
+
+// NewGeneratorWithOptions returns a new generator based on the optional Snapshot, like NewGenerator,
+// but calling into the hooks given by the optional opts (see GeneratorOptions).
+func NewGeneratorWithOptions(snapshot *GeneratorSnapshot, opts *GeneratorOptions, c chan<- *SequenceOverflowNotification) (*Generator, error) {
+	var o GeneratorOptions
+	if opts != nil {
+		o = *opts
+	}
+
 	if snapshot != nil {
-		return newGeneratorFromSnapshot(*snapshot, c)
+		return newGeneratorFromSnapshot(*snapshot, o, c)
+	}
+
+	if opts == nil {
+		return newGeneratorFromDefaults(c)
 	}
 
-	return newGeneratorFromDefaults(c)
+	partition, err := genPartition()
+	if err != nil {
+		return nil, err
+	}
+
+	return newGeneratorFromSnapshot(GeneratorSnapshot{
+		Partition: partitionToPublicRepr(partition),
+	}, o, c)
+}
+
+// NewGeneratorWithCallback returns a new generator based on the optional Snapshot, like NewGenerator,
+// but with the given callback getting called with a SequenceOverflowNotification on each tick while
+// the Generator is overflowing (and once more when it declogs) - as an alternative to a channel, which
+// requires a goroutine draining it and may drop notifications when it is not drained in time.
+//
+// The callback gets called synchronously from the goroutine which handles the overflow and releases
+// blocked callers, so it must not block - and should return quickly, as the release of blocked callers
+// is delayed for as long as it runs.
+func NewGeneratorWithCallback(snapshot *GeneratorSnapshot, fn func(SequenceOverflowNotification)) (*Generator, error) {
+	return NewGeneratorWithOptions(snapshot, &GeneratorOptions{OverflowFunc: fn}, nil)
 }
 
-func newGeneratorFromSnapshot(snapshot GeneratorSnapshot, c chan<- *SequenceOverflowNotification) (*Generator, error) {
+func newGeneratorFromSnapshot(snapshot GeneratorSnapshot, opts GeneratorOptions, c chan<- *SequenceOverflowNotification) (*Generator, error) {
 	if err := sanitizeSnapshotBounds(&snapshot); err != nil {
 		return nil, err
 	}
 
-	return &Generator{
+	if snapshot.Epoch == 0 {
+		snapshot.Epoch = Epoch
+	} else if now := int64(snotime()/250) + Epoch; snapshot.Epoch > now || now-snapshot.Epoch > MaxTimestamp/250 {
+		return nil, &InvalidEpochError{Epoch: snapshot.Epoch}
+	}
+
+	if snapshot.SchemaVersionBits == 0 {
+		snapshot.SchemaVersionBits = DefaultSchemaVersionBits
+	} else if snapshot.SchemaVersionBits > 8 {
+		return nil, &InvalidSchemaVersionBitsError{Bits: snapshot.SchemaVersionBits}
+	}
+
+	g := &Generator{
+		name:            snapshot.Name,
 		partition:       partitionToInternalRepr(snapshot.Partition),
 		seq:             snapshot.Sequence,
+		seqFirst:        uint32(snapshot.SequenceMin),
 		seqMin:          uint32(snapshot.SequenceMin),
 		seqMax:          uint32(snapshot.SequenceMax),
 		seqStatic:       uint32(snapshot.SequenceMin - 1), // Offset by -1 since NewWithTime starts this with an incr.
 		seqOverflowCond: sync.NewCond(&sync.Mutex{}),
 		seqOverflowChan: c,
+		seqOverflowFunc: opts.OverflowFunc,
 		drifts:          snapshot.Drifts,
 		wallHi:          uint64(snapshot.WallHi),
 		wallSafe:        uint64(snapshot.WallSafe),
-	}, nil
+		logical:         snapshot.LogicalClock,
+		oneShot:         snapshot.OneShotPerTimeframe,
+		sum:             snapshot.Checksum,
+		schemaBits:      snapshot.SchemaVersionBits,
+		failFast:        snapshot.FailOnOverflow,
+		floorSave:       opts.SaveFloor,
+		leaser:          opts.Leaser,
+		jitter:          uint32(snapshot.Jitter),
+		gap:             snapshot.MinGap,
+		epoch:           snapshot.Epoch,
+		epochOff:        uint64((snapshot.Epoch - Epoch) * 250),
+		clock:           opts.Clock,
+	}
+
+	if snapshot.ReservedMeta != (MetaSet{}) {
+		g.reserved = new(MetaSet)
+		*g.reserved = snapshot.ReservedMeta
+	}
+
+	if g.jitter > 0 {
+		// The seeds must differ across hosts, so a time based seed won't do.
+		var b [8]byte
+		if _, err := rand.Read(b[:]); err != nil {
+			return nil, err
+		}
+
+		g.jitterState = binary.BigEndian.Uint64(b[:])
+	}
+
+	if g.leaser != nil {
+		if err := g.lease(); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.LoadFloor != nil {
+		g.raiseFloor(opts.LoadFloor())
+	}
+
+	if snapshot.MinFirstID != nil {
+		floor := binary.BigEndian.Uint64(snapshot.MinFirstID[:]) >> 25
+		if now := g.now(); floor > now && floor-now > maxMinFirstIDLead {
+			return nil, &InvalidMinFirstIDError{ID: *snapshot.MinFirstID, Lead: time.Duration(floor-now) * TimeUnit}
+		}
+
+		g.raiseFloor(floor)
+	}
+
+	return g, nil
+}
+
+// raiseFloor makes sure the Generator will not emit IDs with timestamps at or below the given floor.
+func (g *Generator) raiseFloor(floor uint64) {
+	if floor < g.wallHi {
+		return
+	}
+
+	// We pretend to be in the floor timeframe with an exhausted sequence pool. This way the next call
+	// to New() either progresses with the wall clock or - if the wall clock is at or behind the floor -
+	// waits for it to catch up (as the floor also becomes wallSafe) or advances the logical clock.
+	g.wallHi = floor
+	g.seq = g.seqMax + 1
+	g.floor = floor
+	g.leaseWall = floor // Leased Generators must not lease a new band within the floor timeframe either.
+
+	if floor > g.wallSafe {
+		g.wallSafe = floor
+	}
 }
 
 func newGeneratorFromDefaults(c chan<- *SequenceOverflowNotification) (*Generator, error) {
@@ -104,30 +480,506 @@ func newGeneratorFromDefaults(c chan<- *SequenceOverflowNotification) (*Generato
 		partition:       partition,
 		seqMax:          MaxSequence,
 		seqStatic:       ^uint32(0), // Offset by -1 since NewWithTime starts this with an incr.
+		epoch:           Epoch,
 		seqOverflowCond: sync.NewCond(&sync.Mutex{}),
 		seqOverflowChan: c,
 	}, nil
 }
 
 // New generates a new ID using the current system time for its timestamp.
+//
+// Panics with a ReservedMetaError if the given metabyte is reserved (see GeneratorSnapshot.ReservedMeta).
 func (g *Generator) New(meta byte) (id ID) {
+	g.checkMeta(meta)
+
+	units, tick, seq, ok := g.acquire(nil, 1, true)
+	if !ok {
+		return
+	}
+
+	g.applyTimestamp(&id, units, tick)
+	g.applyPayload(&id, meta, seq)
+
+	return
+}
+
+// ReserveNext generates a new ID like New, but also returns the sequence the ID got generated with,
+// e.g. for callers which must record the sequence alongside the ID. Both come from the same reservation,
+// unlike querying Sequence() after a call to New, which races with other callers.
+//
+// Panics with a ReservedMetaError if the given metabyte is reserved (see GeneratorSnapshot.ReservedMeta).
+func (g *Generator) ReserveNext(meta byte) (id ID, seq uint16) {
+	g.checkMeta(meta)
+
+	units, tick, s, ok := g.acquire(nil, 1, true)
+	if !ok {
+		return zero, 0
+	}
+
+	g.applyTimestamp(&id, units, tick)
+	g.applyPayload(&id, meta, s)
+
+	return id, uint16(s)
+}
+
+// NewContext generates a new ID like New, but gives up waiting for the sequence to reset if the given
+// context gets cancelled while the sequence pool is exhausted - returning ctx.Err() in that case, e.g.
+// for request-scoped callers with deadlines. It also returns a ReservedMetaError instead of panicking
+// if the given metabyte is reserved (see GeneratorSnapshot.ReservedMeta).
+//
+// The context only gets consulted when the call would block due to an overflow - it behaves
+// identically to New otherwise, including when the context is already cancelled but the pool has
+// room left. Like TryNew, it can be freely mixed with New on the same Generator. Returns
+// a SequencePoolExhaustedError instead of blocking on overflows if GeneratorSnapshot.FailOnOverflow is set.
+func (g *Generator) NewContext(ctx context.Context, meta byte) (id ID, err error) {
+	if g.reserved != nil && g.reserved[meta] {
+		return zero, &ReservedMetaError{Meta: meta}
+	}
+
+	units, tick, seq, ok := g.acquire(ctx, 1, !g.failFast)
+	if !ok {
+		if err = ctx.Err(); err != nil {
+			return zero, err
+		}
+
+		return zero, &SequencePoolExhaustedError{Sequence: seq, Max: uint16(g.seqMax)}
+	}
+
+	g.applyTimestamp(&id, units, tick)
+	g.applyPayload(&id, meta, seq)
+
+	return id, nil
+}
+
+// TryNew generates a new ID like New, but fails fast instead of blocking: if the sequence pool
+// is exhausted within the current timeframe, it returns a SequencePoolExhaustedError right away
+// instead of waiting for the next timeframe. It also returns a ReservedMetaError instead of panicking
+// if the given metabyte is reserved (see GeneratorSnapshot.ReservedMeta).
+//
+// The overflow policy is chosen per call - TryNew can be freely mixed with New (and the other
+// blocking methods) on the same Generator, e.g. for the few latency-critical callers among many
+// which prefer to wait. Failed calls do not count as blocked in OverflowStats. Note that TryNew
+// still sleeps after multiple wall clock regressions and paces calls if GeneratorSnapshot.MinGap
+// is set, just like New does.
+func (g *Generator) TryNew(meta byte) (id ID, err error) {
+	if g.reserved != nil && g.reserved[meta] {
+		return zero, &ReservedMetaError{Meta: meta}
+	}
+
+	units, tick, seq, ok := g.acquire(nil, 1, false)
+	if !ok {
+		return zero, &SequencePoolExhaustedError{Sequence: seq, Max: uint16(g.seqMax)}
+	}
+
+	g.applyTimestamp(&id, units, tick)
+	g.applyPayload(&id, meta, seq)
+
+	return id, nil
+}
+
+// checkMeta panics with a ReservedMetaError if the given metabyte is reserved.
+func (g *Generator) checkMeta(meta byte) {
+	if g.reserved != nil && g.reserved[meta] {
+		panic(&ReservedMetaError{Meta: meta})
+	}
+}
+
+// NewWithTimeByte generates a new ID like New, but with the low 8 bits of its timestamp's Unix second
+// as its metabyte, i.e. its metabyte equals:
+//	byte(id.Time().Unix())
+//
+// This convention repurposes the metabyte as a coarse, secondary copy of the time, e.g. for archival
+// formats which need to detect corrupted timestamps - the metabyte of a sound ID agrees with the low bits
+// of the second its timestamp decodes to. The metabyte can not carry any other information then.
+func (g *Generator) NewWithTimeByte() (id ID) {
+	units, tick, seq, ok := g.acquire(nil, 1, true)
+	if !ok {
+		return
+	}
+
+	g.applyTimestamp(&id, units, tick)
+	g.applyPayload(&id, byte(units/250+uint64(g.epoch)), seq)
+
+	return
+}
+
+// NewForPartition generates a new ID using the current system time for its timestamp, like New,
+// but with the given Partition instead of the Generator's own, e.g. for gateways generating IDs
+// on behalf of many tenants without having to maintain a Generator for each of them.
+//
+// The time and sequence bookkeeping remains shared across all partitions, so IDs generated within
+// a timeframe are unique regardless of the partition they got generated for - but each call draws
+// from the same sequence pool, so partitions do not get a pool of their own and the sequences
+// of each partition are not dense (IDs for one partition within a timeframe have gaps in their
+// sequences wherever IDs for other partitions got generated in between).
+//
+// Managing collisions with IDs generated by other Generators whose own Partition is the given one
+// is left to the user.
+func (g *Generator) NewForPartition(meta byte, p Partition) (id ID) {
+	g.checkMeta(meta)
+
+	units, tick, seq, ok := g.acquire(nil, 1, true)
+	if !ok {
+		return
+	}
+
+	g.applyTimestamp(&id, units, tick)
+	id[5] = meta
+	binary.BigEndian.PutUint32(id[6:], partitionToInternalRepr(p)|seq)
+
+	if g.sum {
+		id[5] = id.checksum()
+	}
+
+	return
+}
+
+// NewForTenant generates a new ID using the current system time for its timestamp, like New, but
+// with the given 24-bit tenant id embedded across its metabyte (the high 8 bits) and partition
+// (the low 16 bits), e.g. for multi-tenant systems with more than 65536 tenants. The tenant id
+// can be retrieved using ID.Tenant.
+//
+// This repurposes both fields: there is no metabyte left to use freely and the Partition of the
+// Generator is not embedded - the partition of the ID is determined by the tenant. Like with
+// NewForPartition, the time and sequence bookkeeping remains shared across all tenants, so
+// IDs generated by the Generator are unique regardless of their tenant, but managing collisions
+// with IDs generated by other Generators is left to the user. IDs sort by their tenant within
+// a timeframe.
+//
+// Returns an InvalidTenantError if the tenant exceeds MaxTenant. Neither GeneratorSnapshot.Checksum
+// (which would overwrite the high bits of the tenant) nor GeneratorSnapshot.ReservedMeta apply.
+func (g *Generator) NewForTenant(tenant uint32) (id ID, err error) {
+	if tenant > MaxTenant {
+		return zero, &InvalidTenantError{Tenant: tenant}
+	}
+
+	units, tick, seq, ok := g.acquire(nil, 1, !g.failFast)
+	if !ok {
+		return zero, &SequencePoolExhaustedError{Sequence: seq, Max: uint16(g.seqMax)}
+	}
+
+	g.applyTimestamp(&id, units, tick)
+	id[5] = byte(tenant >> 16)
+	binary.BigEndian.PutUint32(id[6:], tenant<<16|seq)
+
+	return id, nil
+}
+
+// NewVersioned generates a new ID using the current system time for its timestamp, like New, but with
+// the given schema version packed into the high bits of its metabyte and the given payload into the low bits,
+// as split by GeneratorSnapshot.SchemaVersionBits. Both can be retrieved using ID.SchemaSplit (or, with
+// the default split, ID.SchemaVersion and ID.SchemaPayload).
+//
+// Returns an InvalidSchemaVersionError if either does not fit the split,
+// a ReservedMetaError if the resulting metabyte is reserved (see GeneratorSnapshot.ReservedMeta) and
+// a SequencePoolExhaustedError instead of blocking on overflows if GeneratorSnapshot.FailOnOverflow is set.
+// GeneratorSnapshot.Checksum does not apply, as it would overwrite the version.
+func (g *Generator) NewVersioned(version uint8, meta uint8) (id ID, err error) {
+	packed, ok := packSchema(version, meta, g.schemaBits)
+	if !ok {
+		return zero, &InvalidSchemaVersionError{Version: version, Payload: meta, Bits: g.schemaBits}
+	}
+
+	if g.reserved != nil && g.reserved[packed] {
+		return zero, &ReservedMetaError{Meta: packed}
+	}
+
+	units, tick, seq, ok := g.acquire(nil, 1, !g.failFast)
+	if !ok {
+		return zero, &SequencePoolExhaustedError{Sequence: seq, Max: uint16(g.seqMax)}
+	}
+
+	g.applyTimestamp(&id, units, tick)
+	id[5] = packed
+	binary.BigEndian.PutUint32(id[6:], g.partition|seq)
+
+	return id, nil
+}
+
+// NewSpaced generates a new ID like New, but in a timeframe of its own: no two IDs generated via NewSpaced
+// share a timeframe, e.g. to guarantee that their order by time is strict even after merging them
+// with IDs of other Generators.
+//
+// Calls get serialized and each waits (by sleeping) until the clock of the Generator moves past the
+// timeframe of the previous ID generated this way - capping the rate at one ID per TimeUnit, that is
+// 250 IDs per second. The timestamps are never future-dated, but the clock must progress for calls
+// to return (which the clocks of golden Generators do not).
+//
+// The timeframes are only distinct among IDs generated via NewSpaced - IDs generated concurrently via New
+// and the other generation methods may still share them.
+//
+// Panics with a ReservedMetaError if the given metabyte is reserved (see GeneratorSnapshot.ReservedMeta).
+func (g *Generator) NewSpaced(meta byte) (id ID) {
+	g.checkMeta(meta)
+
+	g.spacedMu.Lock()
+	defer g.spacedMu.Unlock()
+
+	for {
+		if g.now() <= g.spacedLast {
+			time.Sleep(TimeUnit / 4)
+			continue
+		}
+
+		units, tick, seq, ok := g.acquire(nil, 1, true)
+		if !ok {
+			return
+		}
+
+		// The clock may have regressed (or progressed logically) in the meantime.
+		if units <= g.spacedLast {
+			continue
+		}
+
+		g.spacedLast = units
+		g.applyTimestamp(&id, units, tick)
+		g.applyPayload(&id, meta, seq)
+
+		return
+	}
+}
+
+// seqStart returns the sequence a new timeframe starts at, which is SequenceMin unless
+// jitter is applied. One-shot Generators do not apply jitter, as the ID they repeat within
+// a timeframe is always the one at SequenceMin.
+func (g *Generator) seqStart() uint32 {
+	if g.jitter == 0 || g.oneShot {
+		return g.seqMin
+	}
+
+	// SplitMix64 over a Weyl sequence - which can be advanced atomically.
+	z := atomic.AddUint64(&g.jitterState, 0x9E3779B97F4A7C15)
+	z = (z ^ z>>30) * 0xBF58476D1CE4E5B9
+	z = (z ^ z>>27) * 0x94D049BB133111EB
+	z ^= z >> 31
+
+	return g.seqMin + uint32(z%uint64(g.jitter+1))
+}
+
+// startFrame resets the sequence for a new timeframe, advanced by n from the sequence the timeframe
+// starts at, and records the count of IDs generated in the timeframe of wallHi which it supersedes.
+// Returns the last of the n sequences. Must only be called by whoever advanced the Generator
+// to the new timeframe.
+func (g *Generator) startFrame(wallHi uint64, n uint32) (seq uint32) {
+	start := g.seqStart()
+	seq = start + n - 1
+
+	var (
+		prevSeq   = atomic.SwapUint32(&g.seq, seq)
+		prevFirst = atomic.SwapUint32(&g.seqFirst, start)
+	)
+
+	g.recordFrameLen(wallHi, uint32(g.frameLen(prevSeq, prevFirst)))
+
+	return seq
+}
+
+// NewWithGap generates a new ID like New, but advances the sequence by skip+1 instead of 1,
+// deliberately leaving a gap of skip sequences before the ID - e.g. to test the gap detection
+// of consumers.
+//
+// This is a testing affordance. The skipped sequences are wasted capacity (which may cause
+// the Generator to overflow sooner) and skips which exceed the capacity of the Generator get clamped
+// to Cap()-1 (less the jitter, if any). Gaps are not supported by Generators with a Leaser, where this behaves like New.
+func (g *Generator) NewWithGap(meta byte, skip uint16) (id ID) {
+	g.checkMeta(meta)
+
+	n := uint32(skip) + 1
+	if c := uint32(g.Cap()) - g.jitter; n > c {
+		n = c
+	}
+
+	units, tick, seq, ok := g.acquire(nil, n, true)
+	if !ok {
+		return
+	}
+
+	g.applyTimestamp(&id, units, tick)
+	g.applyPayload(&id, meta, seq)
+
+	return
+}
+
+// NewBatch generates n new IDs like n calls to New would, but reserves their sequences in blocks of up to
+// Cap() at once - a single atomic operation and clock read per block instead of per ID - e.g. for
+// high-throughput ingestion. Returns nil if n <= 0.
+//
+// A block which does not fit into what remains of the sequence pool gets truncated to the remainder and
+// the rest rolls over into the next timeframe - waiting for it, just like New does on overflows. The IDs
+// are unique and ordered the same way as IDs from successive calls to New are, and batches can be mixed
+// freely with concurrent calls to New and the other generation methods on the same Generator (although
+// sequences drawn by those may interleave between the blocks of a batch).
+//
+// Generators with a Leaser, in OneShotPerTimeframe mode or with a MinGap can not reserve blocks and
+// generate the IDs one by one instead.
+//
+// Panics with a ReservedMetaError if the given metabyte is reserved (see GeneratorSnapshot.ReservedMeta).
+func (g *Generator) NewBatch(meta byte, n int) []ID {
+	g.checkMeta(meta)
+
+	return g.newBatch(meta, n, false)
+}
+
+// NewBatchIndexed generates n new IDs like NewBatch, but with the index of each ID within the batch
+// (0 to n-1) as its metabyte, so that a consumer can recover the order of the batch from the IDs alone -
+// even after they got shuffled. Returns nil if n <= 0.
+//
+// The metabyte can hold no more than 256 indices, so batches are limited to 256 IDs, and the metabyte
+// can not carry any other information then. With GeneratorSnapshot.Checksum set, the metabyte holds the
+// checksum instead and the indices can not be recovered.
+//
+// Panics with an InvalidBatchSizeError if n > 256 and with a ReservedMetaError if any of the indices
+// is a reserved metabyte (see GeneratorSnapshot.ReservedMeta).
+func (g *Generator) NewBatchIndexed(n int) []ID {
+	if n > 256 {
+		panic(&InvalidBatchSizeError{Size: n, Max: 256})
+	}
+
+	for i := 0; i < n; i++ {
+		g.checkMeta(byte(i))
+	}
+
+	return g.newBatch(0, n, true)
+}
+
+// newBatch implements NewBatch and NewBatchIndexed. If indexed is set, each ID gets its index within
+// the batch as its metabyte instead of the given one.
+func (g *Generator) newBatch(meta byte, n int, indexed bool) []ID {
+	if n <= 0 {
+		return nil
+	}
+
+	ids := make([]ID, n)
+
+	if g.leaser != nil || g.oneShot || g.gap > 0 {
+		for i := range ids {
+			units, tick, seq, ok := g.acquire(nil, 1, true)
+			if !ok {
+				return ids[:i]
+			}
+
+			if indexed {
+				meta = byte(i)
+			}
+
+			g.applyTimestamp(&ids[i], units, tick)
+			g.applyPayload(&ids[i], meta, seq)
+		}
+
+		return ids
+	}
+
+	// Blocks must fit into a timeframe starting at the highest jittered sequence.
+	size := uint32(g.Cap()) - g.jitter
+
+	for i := 0; i < n; {
+		k := size
+		if r := uint32(n - i); r < k {
+			k = r
+		}
+
+		units, tick, seq, ok := g.acquire(nil, k, false)
+		if !ok {
+			if prev := seq - k; prev < g.seqMax {
+				// The part of the block which still fits into the pool is ours regardless.
+				k, seq = g.seqMax-prev, g.seqMax
+			} else {
+				// Nothing left - wait for the next timeframe and take a single ID there, so that
+				// the next block gets reserved without having to wait again.
+				if units, tick, seq, ok = g.acquire(nil, 1, true); !ok {
+					return ids[:i]
+				}
+
+				k = 1
+			}
+		}
+
+		for seq -= k - 1; k > 0; k-- {
+			if indexed {
+				meta = byte(i)
+			}
+
+			g.applyTimestamp(&ids[i], units, tick)
+			g.applyPayload(&ids[i], meta, seq)
+			seq++
+			i++
+		}
+	}
+
+	return ids
+}
+
+// acquire reserves the timestamp (in sno time units), the tick-tock bit and the sequence
+// for a new ID, advancing the sequence by n (which must be in range [1, Cap()]).
+//
+// When the sequence pool is exhausted, acquire waits for the next timeframe if wait is set - unless the
+// (optional) ctx gets cancelled in the meantime, in which case it gives up and returns with ok set to false.
+// Otherwise it returns immediately with ok set to false and seq set to the sequence which overflowed (along with the timeframe it overflowed in) -
+// without touching any of the overflow bookkeeping blocked callers rely on, so that all kinds of callers
+// can be mixed freely on the same Generator.
+func (g *Generator) acquire(ctx context.Context, n uint32, wait bool) (units uint64, tick uint32, seq uint32, ok bool) {
+	if g.gap > 0 {
+		g.pace()
+	}
+
+	if g.leaser != nil {
+		return g.acquireLeased(ctx, wait)
+	}
+
 retry:
 	var (
 		// Note: Single load of wallHi for the evaluations is correct (as we only grab wallNow
 		// once as well).
 		wallHi  = atomic.LoadUint64(&g.wallHi)
-		wallNow = snotime()
+		wallNow uint64
 	)
 
+	// Same as g.now(), which is too costly to get inlined - and this is the hot path.
+	if g.clock == nil {
+		wallNow = snotime() - g.epochOff
+	} else {
+		wallNow = g.clock()
+	}
+
+	// The logical clock never regresses - we simply remain in the most recent time unit.
+	if g.logical && wallNow < wallHi {
+		wallNow = wallHi
+	}
+
 	// Fastest branch if we're still within the most recent time unit.
 	if wallNow == wallHi {
-		seq := atomic.AddUint32(&g.seq, 1)
+		// The sequence never advances in one-shot mode - the ID of the timeframe simply gets repeated.
+		// Unless the sequence is marked as exhausted (see raiseFloor), that is.
+		if g.oneShot && atomic.LoadUint32(&g.seq) <= g.seqMax {
+			return wallNow, atomic.LoadUint32(&g.drifts) & 1, g.seqMin, true
+		}
+
+		seq = atomic.AddUint32(&g.seq, n)
 
 		if g.seqMax >= seq {
-			g.applyTimestamp(&id, wallNow, atomic.LoadUint32(&g.drifts)&1)
-			g.applyPayload(&id, meta, seq)
+			return wallNow, atomic.LoadUint32(&g.drifts) & 1, seq, true
+		}
 
-			return
+		// Instead of waiting for the wall clock to progress, the logical clock progresses by itself.
+		// Whoever loses the race simply retries within the time unit that got applied.
+		if g.logical {
+			if g.floorSave != nil {
+				g.saveFloor(wallHi + 1)
+			}
+
+			if atomic.CompareAndSwapUint64(&g.wallHi, wallHi, wallHi+1) {
+				seq = g.startFrame(wallHi, n)
+
+				return wallHi + 1, atomic.LoadUint32(&g.drifts) & 1, seq, true
+			}
+
+			goto retry
+		}
+
+		// Overshooting seqMax is harmless - it's what blocked callers do as well. Whoever advances
+		// the Generator to the next timeframe resets the sequence regardless.
+		if !wait {
+			return wallHi, atomic.LoadUint32(&g.drifts) & 1, seq, false
 		}
 
 		// This is to be considered an edge case if seqMax actually gets exceeded, but since bounds
@@ -146,32 +998,62 @@ retry:
 		g.seqOverflowCond.L.Lock()
 		g.seqOverflowCount++
 
+		if g.seqOverflowCount > g.seqOverflowStats.Peak {
+			g.seqOverflowStats.Peak = g.seqOverflowCount
+		}
+
 		if g.seqOverflowTicker == nil {
 			// Tick *roughly* each 1ms during overflows.
 			g.seqOverflowTicker = time.NewTicker(TimeUnit / 4)
+			g.seqOverflowStats.Episodes++
 			go g.seqOverflowLoop()
 		}
 
+		// Cancellation needs to wake us up, but only the cond can - so a watcher broadcasts on our behalf.
+		// It exits once we're done waiting either way, so it never outlives the call.
+		var done chan struct{}
+		if ctx != nil && ctx.Done() != nil {
+			done = make(chan struct{})
+			go g.seqOverflowWatch(ctx, done)
+		}
+
 		for atomic.LoadUint32(&g.seq) > g.seqMax {
+			if ctx != nil && ctx.Err() != nil {
+				break
+			}
+
 			// We spin pessimistically here instead of a straight lock -> wait -> unlock because that'd
 			// put us back on the New(). At extreme contention we could end up back here anyways.
 			g.seqOverflowCond.Wait()
 		}
 
+		if done != nil {
+			close(done)
+		}
+
 		g.seqOverflowCount--
 		g.seqOverflowCond.L.Unlock()
 
+		if ctx != nil && ctx.Err() != nil {
+			return wallHi, 0, seq, false
+		}
+
 		goto retry
 	}
 
 	// Time progression branch.
-	if wallNow > wallHi && atomic.CompareAndSwapUint64(&g.wallHi, wallHi, wallNow) {
-		atomic.StoreUint32(&g.seq, g.seqMin)
+	if wallNow > wallHi {
+		// The floor must be persisted before any ID within the new timeframe gets handed out,
+		// which is why this happens ahead of the CAS - by whoever gets here.
+		if g.floorSave != nil {
+			g.saveFloor(wallNow)
+		}
 
-		g.applyTimestamp(&id, wallNow, atomic.LoadUint32(&g.drifts)&1)
-		g.applyPayload(&id, meta, g.seqMin)
+		if atomic.CompareAndSwapUint64(&g.wallHi, wallHi, wallNow) {
+			seq = g.startFrame(wallHi, n)
 
-		return
+			return wallNow, atomic.LoadUint32(&g.drifts) & 1, seq, true
+		}
 	}
 
 	// Time regression branch.
@@ -192,25 +1074,36 @@ retry:
 		// increases monotonically.
 		atomic.StoreUint64(&g.wallSafe, wallHi)
 		atomic.StoreUint64(&g.wallHi, wallNow)
-		atomic.StoreUint32(&g.seq, g.seqMin)
+		seq = g.startFrame(wallHi, n)
 
-		g.applyTimestamp(&id, wallNow, atomic.AddUint32(&g.drifts, 1)&1)
-		g.applyPayload(&id, meta, g.seqMin)
+		tick = atomic.AddUint32(&g.drifts, 1) & 1
 
 		g.regression.Unlock()
 
-		return
+		return wallNow, tick, seq, true
 	}
 
 	// Branch for all routines that are in an "unsafe" past (e.g. multiple time regressions happened
 	// before we reached wallSafe again).
+	d := time.Duration(g.wallSafe - wallNow)
 	g.regression.Unlock()
 
-	time.Sleep(time.Duration(g.wallSafe - wallNow))
+	g.regressionSleep(d)
 
 	goto retry
 }
 
+// regressionSleep sleeps for d on behalf of a caller waiting for the wall clock to catch up with wallSafe,
+// accounting for the sleep in the stats. The time actually slept gets measured, as wallSafe and the wall
+// clock are in time units - not in the nanoseconds the sleep is requested in - and sleeps tend to overshoot.
+func (g *Generator) regressionSleep(d time.Duration) {
+	start := time.Now()
+	time.Sleep(d)
+
+	atomic.AddUint64(&g.regressionSleeps, 1)
+	atomic.AddUint64(&g.regressionSlept, uint64(time.Since(start)))
+}
+
 // NewWithTime generates a new ID using the given time for the timestamp.
 //
 // IDs generated with user-specified timestamps are exempt from the tick-tock mechanism and
@@ -226,7 +1119,91 @@ retry:
 //
 // This utility is primarily meant to enable porting of old IDs to sno and assumed to be ran
 // before an ID scheme goes online.
+//
+// The time does not get validated: times before the epoch or past MaxTimestamp silently wrap around
+// into IDs with nonsensical timestamps. Use NewWithTimeChecked when the times are not known to be sound.
 func (g *Generator) NewWithTime(meta byte, t time.Time) (id ID) {
+	g.checkMeta(meta)
+
+	return g.newWithUnits(meta, uint64(t.UnixNano()-g.epoch*1e9)/TimeUnit)
+}
+
+// NewWithTimeChecked generates a new ID using the given time for the timestamp like NewWithTime,
+// but validates the time first - e.g. when porting legacy IDs of uncertain quality.
+//
+// Returns a TimestampRangeError if the time falls before the epoch or after the max embeddable
+// timestamp, and a ReservedMetaError instead of panicking if the metabyte is reserved. All other
+// caveats of NewWithTime apply.
+func (g *Generator) NewWithTimeChecked(meta byte, t time.Time) (ID, error) {
+	if g.reserved != nil && g.reserved[meta] {
+		return zero, &ReservedMetaError{Meta: meta}
+	}
+
+	units := g.timeToUnits(t)
+	if units < 0 || units > MaxTimestamp {
+		return zero, timestampRangeError(t, units)
+	}
+
+	return g.newWithUnits(meta, uint64(units)), nil
+}
+
+// timeToUnits converts the given time to sno time units relative to the epoch of the Generator,
+// without validating that they are embeddable.
+func (g *Generator) timeToUnits(t time.Time) int64 {
+	// Computed via seconds, since t.UnixNano() is undefined for times far enough from the Unix epoch.
+	return (t.Unix()-g.epoch)*250 + int64(t.Nanosecond())/TimeUnit
+}
+
+// NewWithUnixNano generates a new ID using the given time, expressed in nanoseconds since the Unix epoch,
+// for the timestamp - without the round-trip through a time.Time.
+//
+// Unlike NewWithTime, the time gets validated: returns a TimestampRangeError if it falls before our
+// epoch or after the max embeddable timestamp. All other caveats of NewWithTime apply.
+func (g *Generator) NewWithUnixNano(meta byte, unixNano int64) (ID, error) {
+	if g.reserved != nil && g.reserved[meta] {
+		return zero, &ReservedMetaError{Meta: meta}
+	}
+
+	units, err := unixNanoToUnits(unixNano, g.epoch*1e9)
+	if err != nil {
+		return zero, err
+	}
+
+	return g.newWithUnits(meta, units), nil
+}
+
+// NewWithTimes generates one ID for each of the given times, in order, e.g. for bulk imports of logs
+// of events with known timestamps. IDs for identical times (or times within the same timeframe) get
+// distinct, ascending sequences drawn from the same sequence as NewWithTime.
+//
+// All times get validated before any ID gets generated: returns a TimestampRangeError for the first
+// time which falls before our epoch or after the max embeddable timestamp, and a ReservedMetaError
+// if the metabyte is reserved. All other caveats of NewWithTime apply - in particular, the sequence
+// rolls over silently when more than Cap() IDs share a timeframe.
+func (g *Generator) NewWithTimes(meta byte, times []time.Time) ([]ID, error) {
+	if g.reserved != nil && g.reserved[meta] {
+		return nil, &ReservedMetaError{Meta: meta}
+	}
+
+	units := make([]uint64, len(times))
+	for i, t := range times {
+		u := g.timeToUnits(t)
+		if u < 0 || u > MaxTimestamp {
+			return nil, timestampRangeError(t, u)
+		}
+
+		units[i] = uint64(u)
+	}
+
+	ids := make([]ID, len(times))
+	for i := range units {
+		ids[i] = g.newWithUnits(meta, units[i])
+	}
+
+	return ids, nil
+}
+
+func (g *Generator) newWithUnits(meta byte, units uint64) (id ID) {
 retry:
 	var seq = atomic.AddUint32(&g.seqStatic, 1)
 
@@ -238,12 +1215,104 @@ retry:
 		seq = g.seqMin
 	}
 
-	g.applyTimestamp(&id, uint64(t.UnixNano()-epochNsec)/TimeUnit, 0)
+	g.applyTimestamp(&id, units, 0)
 	g.applyPayload(&id, meta, seq)
 
 	return
 }
 
+// unixNanoToUnits translates the given Unix time in nanoseconds into a timestamp in sno time units
+// relative to the given origin (the epoch in Unix nanoseconds), returning a TimestampRangeError
+// if it can not be embedded in an ID.
+func unixNanoToUnits(unixNano, origin int64) (uint64, error) {
+	// Checked before the subtraction, since the latter would overflow for times far enough in the past.
+	if unixNano < origin {
+		return 0, timestampRangeError(time.Unix(0, unixNano), unixNano/TimeUnit-origin/TimeUnit)
+	}
+
+	units := (unixNano - origin) / TimeUnit
+	if units > MaxTimestamp {
+		return 0, timestampRangeError(time.Unix(0, unixNano), units)
+	}
+
+	return uint64(units), nil
+}
+
+// NewFromContent generates a new ID using the current system time for its timestamp, but with
+// its metabyte and sequence derived from a hash of the given content instead.
+//
+// IDs generated this way sort by time like any other, but are stable for identical content
+// within a timeframe - that is, the same content within the same timeframe always results in
+// the same ID, which is the point. Different content may collide as well (with a probability of
+// roughly 1 in 2^24 for any two pieces of content within a timeframe), so this is not suitable
+// where uniqueness is required.
+//
+// The derived sequence ignores the sequence bounds of the Generator and does not count towards
+// its sequence pool, so IDs generated this way may collide with those generated by New()
+// within the same timeframe.
+func (g *Generator) NewFromContent(content []byte) (id ID) {
+	var (
+		wallNow = g.now()
+		h       = fnv32a(content)
+	)
+
+	if wallHi := atomic.LoadUint64(&g.wallHi); g.logical && wallNow < wallHi {
+		wallNow = wallHi
+	}
+
+	// XOR-fold the high byte into the lower 24 bits, which then become meta and sequence.
+	h = (h>>24 ^ h) & 0xFFFFFF
+
+	g.applyTimestamp(&id, wallNow, atomic.LoadUint32(&g.drifts)&1)
+	id[5] = byte(h >> 16)
+	binary.BigEndian.PutUint32(id[6:], g.partition|h&0xFFFF)
+
+	if g.sum {
+		id[5] = id.checksum()
+	}
+
+	return
+}
+
+// NewWithCounter generates a new ID using the current system time for its timestamp, but with
+// the low 16 bits of the given counter as its sequence instead of one drawn from the Generator's
+// own pool, e.g. for systems which already maintain a global monotonic counter (like a database
+// sequence) and rely on it for uniqueness.
+//
+// Only 16 bits of the counter fit in a sequence, so it effectively wraps around every 65536 values:
+// IDs generated within the same timeframe are distinct as long as the counters given within that
+// timeframe are distinct modulo 65536, e.g. as long as a monotonic counter advances by less than
+// 65536 within 4msec.
+//
+// The sequence ignores the sequence bounds of the Generator and does not count towards its sequence
+// pool, so IDs generated this way may collide with those generated by New() within the same timeframe.
+//
+// Panics with a ReservedMetaError if the given metabyte is reserved (see GeneratorSnapshot.ReservedMeta).
+func (g *Generator) NewWithCounter(meta byte, counter uint64) (id ID) {
+	g.checkMeta(meta)
+
+	wallNow := g.now()
+	if wallHi := atomic.LoadUint64(&g.wallHi); g.logical && wallNow < wallHi {
+		wallNow = wallHi
+	}
+
+	g.applyTimestamp(&id, wallNow, atomic.LoadUint32(&g.drifts)&1)
+	g.applyPayload(&id, meta, uint32(counter&MaxSequence))
+
+	return
+}
+
+// Name returns the label of the Generator, if one was given. See GeneratorSnapshot.Name.
+func (g *Generator) Name() string {
+	return g.name
+}
+
+// Epoch returns the epoch the Generator embeds timestamps relative to, in seconds since the Unix epoch.
+// See GeneratorSnapshot.Epoch.
+func (g *Generator) Epoch() int64 {
+	return g.epoch
+}
+
 // Partition returns the fixed identifier of the Generator.
 func (g *Generator) Partition() Partition {
 	return partitionToPublicRepr(g.partition)
@@ -262,7 +1331,7 @@ func (g *Generator) Partition() Partition {
 // determine the current overflow via:
 //	overflow := int(uint32(generator.SequenceMax()) - generator.Sequence())
 func (g *Generator) Sequence() uint32 {
-	if wallNow := snotime(); wallNow == atomic.LoadUint64(&g.wallHi) {
+	if g.inFrame(g.now(), atomic.LoadUint64(&g.wallHi)) {
 		return atomic.LoadUint32(&g.seq)
 	}
 
@@ -281,12 +1350,8 @@ func (g *Generator) SequenceMax() uint16 {
 
 // Len returns the number of IDs generated in the current timeframe.
 func (g *Generator) Len() int {
-	if wallNow := snotime(); wallNow == atomic.LoadUint64(&g.wallHi) {
-		if seq := atomic.LoadUint32(&g.seq); g.seqMax > seq {
-			return int(seq-g.seqMin) + 1
-		}
-
-		return g.Cap()
+	if g.inFrame(g.now(), atomic.LoadUint64(&g.wallHi)) {
+		return g.frameLen(atomic.LoadUint32(&g.seq), atomic.LoadUint32(&g.seqFirst))
 	}
 
 	return 0
@@ -297,28 +1362,138 @@ func (g *Generator) Len() int {
 // To get its current capacity (e.g. number of possible additional IDs in the current
 // timeframe), simply:
 // 	spare := generator.Cap() - generator.Len()
-// The result will always be non-negative.
+// The result will always be non-negative, but overstates the spare capacity by up to Jitter
+// if the timeframe started at a jittered sequence (see GeneratorSnapshot.Jitter).
 func (g *Generator) Cap() int {
 	return int(g.seqMax-g.seqMin) + 1
 }
 
+// CapacityUntil returns the number of IDs the Generator could generate from now until the given deadline,
+// that is the spare capacity of the current timeframe plus Cap() IDs for each timeframe starting
+// before the deadline. Returns 0 if the deadline has already passed.
+//
+// It is a theoretical maximum assuming full utilization of the sequence pool in each timeframe - the
+// actual number will be lower, e.g. when the Generator gets used concurrently or the pool gets
+// jittered (see GeneratorSnapshot.Jitter).
+func (g *Generator) CapacityUntil(deadline time.Time) int64 {
+	var (
+		wallNow = g.now()
+		wallHi  = atomic.LoadUint64(&g.wallHi)
+		d       = deadline.UnixNano() - g.epoch*1e9
+	)
+
+	// The logical clock may run ahead of the wall clock, in which case the timeframes up to
+	// its current one have already been borrowed.
+	if g.logical && wallNow < wallHi {
+		wallNow = wallHi
+	}
+
+	if d <= 0 || uint64(d) <= wallNow*TimeUnit {
+		return 0
+	}
+
+	var (
+		last  = uint64(d-1) / TimeUnit // The last timeframe starting before the deadline.
+		spare = int64(g.Cap())
+	)
+
+	// What is left of the current timeframe - which is less than Cap() - Len() if it started at
+	// a jittered sequence.
+	if g.inFrame(wallNow, wallHi) {
+		if seq := atomic.LoadUint32(&g.seq); seq < g.seqMax {
+			spare = int64(g.seqMax - seq)
+		} else {
+			spare = 0
+		}
+	}
+
+	return spare + int64(last-wallNow)*int64(g.Cap())
+}
+
+// CompatibleWith checks whether the IDs generated by the Generator and the other Generator can not
+// collide, based on their configuration - that is, they either have different Partitions or their
+// sequence bounds are disjoint. When they are incompatible, the reason gets returned as well.
+//
+// IDs generated via NewWithTime and NewForPartition are not accounted for.
+func (g *Generator) CompatibleWith(other *Generator) (bool, string) {
+	if g.partition != other.partition {
+		return true, ""
+	}
+
+	if g.seqMin <= other.seqMax && other.seqMin <= g.seqMax {
+		return false, fmt.Sprintf("generators share partition %d and their sequence bounds [%d, %d] and [%d, %d] overlap",
+			g.Partition().AsUint16(), g.seqMin, g.seqMax, other.seqMin, other.seqMax)
+	}
+
+	return true, ""
+}
+
+// SpaceOverlap quantifies the collision risk of co-deploying the two Generators, based on their
+// configuration: it returns the fraction of the (partition × sequence) space of IDs the two share, relative
+// to the space they cover together - that is, the size of the intersection of their sequence pools
+// divided by the size of their union, if they share a Partition, or 0 otherwise.
+//
+// 0 means IDs generated by the two can not collide (see CompatibleWith), 1 that they draw from
+// exactly the same space, and values in between that the pools partially overlap. It is a measure of
+// the shared space, not a probability - how likely collisions actually are depends on the rates of the
+// Generators, as sequences only collide within the same timeframe.
+//
+// IDs generated via NewWithTime and NewForPartition are not accounted for.
+func SpaceOverlap(a, b *Generator) float64 {
+	if a.partition != b.partition {
+		return 0
+	}
+
+	lo, hi := a.seqMin, a.seqMax
+	if b.seqMin > lo {
+		lo = b.seqMin
+	}
+
+	if b.seqMax < hi {
+		hi = b.seqMax
+	}
+
+	if lo > hi {
+		return 0
+	}
+
+	var (
+		shared = float64(hi-lo) + 1
+		union  = float64(a.Cap()+b.Cap()) - shared
+	)
+
+	return shared / union
+}
+
+// HasOverflowChannel checks whether the Generator has been constructed with a channel
+// to send SequenceOverflowNotifications to.
+func (g *Generator) HasOverflowChannel() bool {
+	return g.seqOverflowChan != nil
+}
+
 // Snapshot returns a copy of the Generator's current bookkeeping data.
 func (g *Generator) Snapshot() GeneratorSnapshot {
 	var (
-		wallNow = snotime()
+		wallNow = g.now()
 		wallHi  = atomic.LoadUint64(&g.wallHi)
 		seq     uint32
 	)
 
 	// Be consistent with g.Sequence() and return seqMin if the next call to New()
 	// would reset the sequence.
-	if wallNow == wallHi {
+	if g.inFrame(wallNow, wallHi) {
 		seq = atomic.LoadUint32(&g.seq)
 	} else {
 		seq = g.seqMin
 	}
 
+	var reserved MetaSet
+	if g.reserved != nil {
+		reserved = *g.reserved
+	}
+
 	return GeneratorSnapshot{
+		Name:        g.name,
 		Partition:   partitionToPublicRepr(g.partition),
 		SequenceMin: uint16(g.seqMin),
 		SequenceMax: uint16(g.seqMax),
@@ -327,7 +1502,88 @@ func (g *Generator) Snapshot() GeneratorSnapshot {
 		WallHi:      int64(wallHi),
 		WallSafe:    int64(atomic.LoadUint64(&g.wallSafe)),
 		Drifts:      atomic.LoadUint32(&g.drifts),
+		Epoch:       g.epoch,
+
+		LogicalClock:        g.logical,
+		OneShotPerTimeframe: g.oneShot,
+		FailOnOverflow:      g.failFast,
+		Checksum:            g.sum,
+		SchemaVersionBits:   g.schemaBits,
+		Jitter:              uint16(g.jitter),
+		MinGap:              g.gap,
+		ReservedMeta:        reserved,
+	}
+}
+
+// pace blocks until at least MinGap has elapsed since the previous call got released.
+func (g *Generator) pace() {
+	g.gapMu.Lock()
+
+	// time.Now() carries a monotonic clock reading, so the gap is unaffected by wall clock changes.
+	if wait := g.gap - time.Since(g.gapLast); !g.gapLast.IsZero() && wait > 0 {
+		time.Sleep(wait)
+	}
+
+	g.gapLast = time.Now()
+	g.gapMu.Unlock()
+}
+
+// now returns the current time of the Generator's clock, in sno time units and in its epoch.
+func (g *Generator) now() uint64 {
+	if g.clock != nil {
+		return g.clock()
 	}
+
+	return snotime() - g.epochOff
+}
+
+// saveFloor persists the given timeframe as the floor, unless a higher floor has already been saved.
+func (g *Generator) saveFloor(wall uint64) {
+	g.floorMu.Lock()
+	if wall > g.floor {
+		g.floorSave(wall)
+		g.floor = wall
+	}
+	g.floorMu.Unlock()
+}
+
+// GeneratorView is a read-only view of a Generator, which allows for introspecting its state
+// but not for generating IDs. See Generator.ReadOnly.
+type GeneratorView interface {
+	Partition() Partition
+	SequenceMin() uint16
+	SequenceMax() uint16
+	Cap() int
+	Len() int
+	Sequence() uint32
+	Snapshot() GeneratorSnapshot
+}
+
+// ReadOnly returns a read-only view of the Generator, e.g. to pass on to components which
+// should only introspect it.
+//
+// The view can not be converted back into the Generator via a type assertion.
+func (g *Generator) ReadOnly() GeneratorView {
+	return generatorView{g: g}
+}
+
+// generatorView wraps a Generator to only expose the methods of a GeneratorView.
+type generatorView struct {
+	g *Generator
+}
+
+func (v generatorView) Partition() Partition        { return v.g.Partition() }
+func (v generatorView) SequenceMin() uint16         { return v.g.SequenceMin() }
+func (v generatorView) SequenceMax() uint16         { return v.g.SequenceMax() }
+func (v generatorView) Cap() int                    { return v.g.Cap() }
+func (v generatorView) Len() int                    { return v.g.Len() }
+func (v generatorView) Sequence() uint32            { return v.g.Sequence() }
+func (v generatorView) Snapshot() GeneratorSnapshot { return v.g.Snapshot() }
+
+// inFrame reports whether the next call to New() would generate an ID within the timeframe of wallHi,
+// given the current wall clock time.
+func (g *Generator) inFrame(wallNow, wallHi uint64) bool {
+	return wallNow == wallHi || g.logical && wallNow < wallHi
 }
 
 func (g *Generator) applyTimestamp(id *ID, units uint64, tick uint32) {
@@ -350,16 +1606,47 @@ func (g *Generator) applyTimestamp(id *ID, units uint64, tick uint32) {
 func (g *Generator) applyPayload(id *ID, meta byte, seq uint32) {
 	id[5] = meta
 	binary.BigEndian.PutUint32(id[6:], g.partition|seq)
+
+	// The checksum covers all other bytes, so it must come last.
+	if g.sum {
+		id[5] = id.checksum()
+	}
+}
+
+// seqOverflowWatch wakes up all callers waiting for the sequence to reset once ctx gets cancelled,
+// so that the caller which waits on behalf of ctx notices - unless done gets closed first.
+func (g *Generator) seqOverflowWatch(ctx context.Context, done <-chan struct{}) {
+	select {
+	case <-ctx.Done():
+		g.seqOverflowCond.L.Lock()
+		g.seqOverflowCond.Broadcast()
+		g.seqOverflowCond.L.Unlock()
+	case <-done:
+	}
 }
 
 func (g *Generator) seqOverflowLoop() {
 	var (
 		retryNotify bool
 		ticks       uint32
+		notify      SequenceOverflowNotification
 	)
 
 	for t := range g.seqOverflowTicker.C {
 		g.seqOverflowCond.L.Lock()
+		g.seqOverflowStats.Ticks++
+
+		if g.seqOverflowFunc != nil {
+			// Unlike the channel, the callback gets notified on each tick. The notification gets captured
+			// under the lock, but the callback only gets called once it's released.
+			ticks++
+			notify = SequenceOverflowNotification{
+				Name:  g.name,
+				Now:   t,
+				Ticks: ticks,
+				Count: g.seqOverflowCount,
+			}
+		}
 
 		if g.seqOverflowChan != nil {
 			// We only ever count ticks when we've got a notification channel up.
@@ -369,6 +1656,7 @@ func (g *Generator) seqOverflowLoop() {
 			if retryNotify || g.seqOverflowCount == 0 || ticks%4 == 1 {
 				select {
 				case g.seqOverflowChan <- &SequenceOverflowNotification{
+					Name:  g.name,
 					Now:   t,
 					Ticks: ticks,
 					Count: g.seqOverflowCount,
@@ -388,6 +1676,10 @@ func (g *Generator) seqOverflowLoop() {
 			g.seqOverflowTicker = nil
 			g.seqOverflowCond.L.Unlock()
 
+			if g.seqOverflowFunc != nil {
+				g.seqOverflowFunc(notify)
+			}
+
 			return
 		}
 
@@ -395,6 +1687,10 @@ func (g *Generator) seqOverflowLoop() {
 		// The broadcasts further don't require us to hold the lock.
 		g.seqOverflowCond.L.Unlock()
 
+		if g.seqOverflowFunc != nil {
+			g.seqOverflowFunc(notify)
+		}
+
 		// Under normal behaviour high load would trigger an overflow and load would remain roughly
 		// steady, so a seq reset will simply get triggered by a time change happening in New().
 		// The actual callers are in a pessimistic loop and will check the condition themselves again.
@@ -407,10 +1703,15 @@ func (g *Generator) seqOverflowLoop() {
 		// Handles an edge case where we've got calls locked on an overflow and suddenly no more
 		// calls to New() come in, meaning there's no one to actually reset the sequence.
 		var (
-			wallNow = uint64(t.UnixNano()-epochNsec) / TimeUnit
+			wallNow = uint64(t.UnixNano()-g.epoch*1e9) / TimeUnit
 			wallHi  = atomic.LoadUint64(&g.wallHi)
 		)
 
+		// A custom clock need not agree with the ticker.
+		if g.clock != nil {
+			wallNow = g.now()
+		}
+
 		if wallNow > wallHi {
 			atomic.StoreUint32(&g.seq, g.seqMin)
 			g.seqOverflowCond.Broadcast()
@@ -420,6 +1721,9 @@ func (g *Generator) seqOverflowLoop() {
 	}
 }
 
+// How far (in sno time units) GeneratorSnapshot.MinFirstID may lie ahead of the current time. One minute.
+const maxMinFirstIDLead = 60 * 250
+
 // Arbitrary min pool size of 4 per time unit (that is 1000 per sec).
 // Separated out as a constant as this value is being tested against.
 const minSequencePoolSize = 4
@@ -431,17 +1735,8 @@ func sanitizeSnapshotBounds(s *GeneratorSnapshot) error {
 		s.SequenceMax = MaxSequence
 	}
 
-	if s.SequenceMin == s.SequenceMax {
-		return invalidSequenceBounds(s, errSequenceBoundsIdenticalMsg)
-	}
-
-	// Allow bounds to be given in any order.
-	if s.SequenceMax < s.SequenceMin {
-		s.SequenceMin, s.SequenceMax = s.SequenceMax, s.SequenceMin
-	}
-
-	if s.SequenceMax-s.SequenceMin-1 < minSequencePoolSize {
-		return invalidSequenceBounds(s, errSequencePoolTooSmallMsg)
+	if msg := checkSequenceBounds(&s.SequenceMin, &s.SequenceMax); msg != "" {
+		return invalidSequenceBounds(s, msg)
 	}
 
 	// Allow zero value to pass as a default of the lower bound.
@@ -453,6 +1748,65 @@ func sanitizeSnapshotBounds(s *GeneratorSnapshot) error {
 		return invalidSequenceBounds(s, errSequenceUnderflowsBound)
 	}
 
+	if uint32(s.Jitter) > uint32(s.SequenceMax-s.SequenceMin)+1-minSequencePoolSize {
+		return invalidSequenceBounds(s, errSequenceJitterTooLargeMsg)
+	}
+
+	return nil
+}
+
+// checkSequenceBounds orders the given bounds and returns the message of the error they
+// violate, if any.
+func checkSequenceBounds(min, max *uint16) string {
+	if *min == *max {
+		return errSequenceBoundsIdenticalMsg
+	}
+
+	// Allow bounds to be given in any order.
+	if *max < *min {
+		*min, *max = *max, *min
+	}
+
+	if *max-*min-1 < minSequencePoolSize {
+		return errSequencePoolTooSmallMsg
+	}
+
+	return ""
+}
+
+// ValidatePartitioning checks whether the given sequence ranges (bounds inclusive, in either order)
+// can be used as the sequence bounds of Generators sharing a single Partition, e.g. when manually
+// carving up the sequence space across multiple Generators.
+//
+// Returns InvalidSequenceBoundsError if any of the ranges is not a valid sequence pool on its own
+// (the same way NewGenerator would) and SequenceRangesOverlapError if any two ranges overlap.
+// Adjacent ranges are valid.
+func ValidatePartitioning(ranges [][2]uint16) error {
+	sorted := make([][2]uint16, len(ranges))
+	for i, r := range ranges {
+		if msg := checkSequenceBounds(&r[0], &r[1]); msg != "" {
+			return &InvalidSequenceBoundsError{
+				Cur: uint32(r[0]),
+				Min: r[0],
+				Max: r[1],
+				Msg: msg,
+			}
+		}
+
+		sorted[i] = r
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i][0] < sorted[j][0]
+	})
+
+	// Once sorted by their lower bounds, it suffices to check each range against its predecessor.
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i][0] <= sorted[i-1][1] {
+			return &SequenceRangesOverlapError{A: sorted[i-1], B: sorted[i]}
+		}
+	}
+
 	return nil
 }
 
@@ -3,7 +3,12 @@
 package sno
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"math/rand"
+	"reflect"
+	"runtime"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -185,6 +190,46 @@ func TestGenerator_NewOverflows(t *testing.T) {
 	}
 }
 
+func TestGenerator_Name(t *testing.T) {
+	var (
+		c      = make(chan *SequenceOverflowNotification, 64)
+		g, err = NewGenerator(&GeneratorSnapshot{
+			Name:        "primary",
+			SequenceMin: 0,
+			SequenceMax: 7,
+		}, c)
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if actual, expected := g.Name(), "primary"; actual != expected {
+		t.Errorf("expected [%s], got [%s]", expected, actual)
+	}
+
+	if actual, expected := g.Snapshot().Name, "primary"; actual != expected {
+		t.Errorf("expected [%s], got [%s]", expected, actual)
+	}
+
+	if actual, expected := g.Stats().Name, "primary"; actual != expected {
+		t.Errorf("expected [%s], got [%s]", expected, actual)
+	}
+
+	// Overflow the tiny pool to get notified.
+	for i := 0; i < 4*g.Cap(); i++ {
+		g.New(255)
+	}
+
+	select {
+	case note := <-c:
+		if actual, expected := note.Name, "primary"; actual != expected {
+			t.Errorf("expected [%s], got [%s]", expected, actual)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected an overflow notification")
+	}
+}
+
 func TestGenerator_NewTickTocks(t *testing.T) {
 	g, ids := testGeneratorNewTickTocksSetup(t)
 	t.Run("Tick", testGeneratorNewTickTocksTick(g, ids))
@@ -293,6 +338,16 @@ func testGeneratorNewTickTocksSafetySlumber(g *Generator, ids []ID) func(*testin
 			t.Errorf("expected [1] drift recorded, got [%d]", atomic.LoadUint32(&g.drifts))
 		}
 
+		// The sleep must be accounted for in the stats, unlike the drift.
+		stats := g.Stats()
+		if stats.RegressionSleeps == 0 {
+			t.Error("expected at least [1] regression sleep recorded, got [0]")
+		}
+
+		if stats.RegressionSleepTime < TimeUnit || stats.RegressionSleepTime > time.Duration(monoDiff) {
+			t.Errorf("expected regression sleep time in range [%d, %d], got [%d]", time.Duration(TimeUnit), monoDiff, stats.RegressionSleepTime)
+		}
+
 		snotime = internal.Snotime
 	}
 }
@@ -437,428 +492,2348 @@ func TestGenerator_NewGeneratorRestoreRegressions(t *testing.T) {
 	}
 }
 
-func TestGenerator_NewWithTimeOverflows(t *testing.T) {
-	var (
-		part         = Partition{255, 255}
-		seqPool      = 12
-		seqOverflows = 4
-		seqMin       = uint16(seqPool)
-		seqMax       = uint16(2*seqPool - 1)
-		sampleSize   = seqPool * seqOverflows
+func TestGenerator_NewWithUnixNano(t *testing.T) {
+	g, err := NewGenerator(&GeneratorSnapshot{
+		Partition:   Partition{255, 254},
+		SequenceMin: 1024,
+		SequenceMax: 2047,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
 
-		g, err = NewGenerator(&GeneratorSnapshot{
-			Partition:   part,
-			SequenceMin: seqMin,
-			SequenceMax: seqMax,
-		}, nil)
-	)
+	tn := time.Now()
 
+	id, err := g.NewWithUnixNano(255, tn.UnixNano())
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	tn := time.Now()
-	pool := g.Cap()
+	// Same as NewWithTime, save for the static sequence having moved on.
+	expected := g.NewWithTime(255, tn)
+	if actual := id; actual.Timestamp() != expected.Timestamp() || actual.Partition() != expected.Partition() {
+		t.Errorf("expected [%v], got [%v]", expected, actual)
+	}
 
-	ids := make([]ID, sampleSize)
-	for i := 0; i < sampleSize; i++ {
-		ids[i] = g.NewWithTime(byte(i), tn)
+	if actual, expected := id.Sequence()+1, expected.Sequence(); actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
 	}
 
-	timeDist := make(map[int64]int)
+	id, err = g.NewWithUnixNano(255, epochNsec-1)
+	if _, ok := err.(*TimestampRangeError); !ok {
+		t.Fatalf("expected error type [%T], got [%T]", &TimestampRangeError{}, err)
+	}
 
-	for i, s := 0, 0; i < sampleSize; i, s = i+1, s+1 {
-		id := ids[i]
-		timeDist[id.Timestamp()]++
+	if !id.IsZero() {
+		t.Errorf("expected zero ID, got [%s]", id)
+	}
+}
 
-		seq := id.Sequence()
-		if seq > seqMax {
-			t.Errorf("%d: sequence overflowing max boundary; max [%d], got [%d]", i, seqMin, seq)
-		}
+func TestGenerator_NewWithTimeChecked(t *testing.T) {
+	g, err := NewGenerator(&GeneratorSnapshot{
+		Partition:   Partition{255, 239},
+		SequenceMin: 1024,
+		SequenceMax: 2047,
+		ReservedMeta: MetaSet{
+			1: true,
+		},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
 
-		if seq < seqMin {
-			t.Errorf("%d: sequence underflowing min boundary; min [%d], got [%d]", i, seqMin, seq)
+	for _, tn := range []time.Time{
+		time.Unix(Epoch, 0),
+		time.Date(2020, 2, 29, 12, 34, 56, 789012345, time.UTC),
+		TimestampExhaustionDate(),
+		TimestampExhaustionDate().Add(TimeUnit - 1),
+	} {
+		id, err := g.NewWithTimeChecked(255, tn)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got [%v]", tn, err)
 		}
 
-		// When we overflow with NewWithTime, the static sequence is supposed to roll over silently.
-		if s == pool {
-			s = 0
-		} else if i > 0 && seq-ids[i-1].Sequence() != 1 {
-			t.Errorf("%d: expected sequence to increment by 1, got [%d]", i, seq-ids[i-1].Sequence())
+		if actual, expected := id.Time(), tn.Truncate(TimeUnit); !actual.Equal(expected) {
+			t.Errorf("expected [%s], got [%s]", expected, actual)
 		}
+	}
 
-		expectedSeq := uint16(s) + seqMin
-		if seq != expectedSeq {
-			t.Errorf("%d: expected sequence [%d], got [%d]", i, expectedSeq, seq)
+	for _, c := range []struct {
+		t     time.Time
+		units int64
+	}{
+		{time.Unix(Epoch, 0).Add(-1), -1},
+		{time.Date(1999, 1, 1, 0, 0, 0, 0, time.UTC), -86788800000},
+		{TimestampExhaustionDate().Add(TimeUnit), MaxTimestamp + 1},
+		{time.Date(3000, 1, 1, 0, 0, 0, 0, time.UTC), (32503680000 - Epoch) * 250},
+	} {
+		id, err := g.NewWithTimeChecked(255, c.t)
+		if actual, expected := err, timestampRangeError(c.t, c.units); !reflect.DeepEqual(actual, expected) {
+			t.Errorf("expected [%v], got [%v]", expected, actual)
 		}
 
-		if id.Partition() != part {
-			t.Errorf("%d: partition differs from generator's partition; expected [%d], got [%d]", i, part, id.Partition())
+		if !id.IsZero() {
+			t.Errorf("expected zero ID, got [%s]", id)
 		}
 	}
 
-	if len(timeDist) > 1 {
-		t.Error("IDs generated with the same time ended up with different timestamps")
+	if _, err := g.NewWithTimeChecked(1, time.Now()); !reflect.DeepEqual(err, &ReservedMetaError{Meta: 1}) {
+		t.Errorf("expected [%v], got [%v]", &ReservedMetaError{Meta: 1}, err)
 	}
+}
 
-	// Race test.
-	var wg sync.WaitGroup
-	wg.Add(1000)
-	for i := 0; i < 1000; i++ {
-		go func() {
-			for i := 0; i < sampleSize; i++ {
-				_ = g.NewWithTime(byte(i), tn)
-			}
-			wg.Done()
-		}()
+func TestGenerator_CapacityUntil(t *testing.T) {
+	g, err := NewGenerator(&GeneratorSnapshot{
+		Partition:   Partition{255, 253},
+		SequenceMin: 1024,
+		SequenceMax: 2047,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
 	}
-	wg.Wait()
-}
 
-func TestGenerator_Uniqueness(t *testing.T) {
 	var (
-		collisions int
-		setSize    = 4 * MaxSequence
+		wall  = atomic.LoadUint64(staticWallNow)
+		start = func(wall uint64) time.Time {
+			return time.Unix(0, int64(wall*TimeUnit)+epochNsec)
+		}
 	)
 
-	ids := make(map[ID]struct{}, setSize)
+	snotime = staticTime
+	defer func() { snotime = internal.Snotime }()
 
-	for i := 1; i < setSize; i++ {
-		id := generator.New(255)
-		if _, found := ids[id]; found {
-			collisions++
-		} else {
-			ids[id] = struct{}{}
-		}
+	for i := 0; i < 24; i++ {
+		g.New(255)
 	}
 
-	if collisions > 0 {
-		t.Errorf("generated %d colliding IDs in a set of %d", collisions, setSize)
+	for _, c := range []struct {
+		name     string
+		deadline time.Time
+		expected int64
+	}{
+		{"past", start(wall - 1), 0},
+		{"current-frame-start", start(wall), 0},
+		{"current-frame", start(wall).Add(TimeUnit / 2), 1000},
+		{"next-frame-start", start(wall + 1), 1000},
+		{"near", start(wall + 10).Add(1), 1000 + 10*1024},
+		{"far", start(wall).Add(time.Hour), 1000 + (3600*250-1)*1024},
+	} {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			if actual, expected := g.CapacityUntil(c.deadline), c.expected; actual != expected {
+				t.Errorf("expected [%d], got [%d]", expected, actual)
+			}
+		})
 	}
 }
 
-func TestGenerator_Partition(t *testing.T) {
-	expected := Partition{'A', 255}
-	g, err := NewGenerator(&GeneratorSnapshot{
-		Partition: expected,
-	}, nil)
+func TestGenerator_MinGap(t *testing.T) {
+	var (
+		gap    = 5 * time.Millisecond
+		g, err = NewGenerator(&GeneratorSnapshot{
+			Partition: Partition{255, 252},
+			MinGap:    gap,
+		}, nil)
+	)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	actual := g.Partition()
-	if actual != expected {
+	if actual, expected := g.Snapshot().MinGap, gap; actual != expected {
 		t.Errorf("expected [%s], got [%s]", expected, actual)
 	}
+
+	var (
+		n     = 4
+		start = monotime()
+		wg    sync.WaitGroup
+	)
+
+	// Concurrent callers get paced just the same - the first call goes through immediately,
+	// all subsequent ones one gap apart.
+	wg.Add(2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			for j := 0; j < n; j++ {
+				g.New(255)
+			}
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+
+	if actual, expected := time.Duration(monotime()-start), time.Duration(2*n-1)*gap; actual < expected {
+		t.Errorf("expected at least [%s] to elapse, got [%s]", expected, actual)
+	}
 }
 
-func TestGenerator_SequenceBounds(t *testing.T) {
-	min := uint16(1024)
-	max := uint16(2047)
+func TestGenerator_ReservedMeta(t *testing.T) {
 	g, err := NewGenerator(&GeneratorSnapshot{
-		SequenceMin: min,
-		SequenceMax: max,
+		Partition:    Partition{255, 251},
+		ReservedMeta: MetaSet{255: true, 254: false},
 	}, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	if actual, expected := g.SequenceMin(), min; actual != expected {
-		t.Errorf("expected [%d], got [%d]", expected, actual)
+	if actual, expected := g.Snapshot().ReservedMeta, (MetaSet{255: true}); actual != expected {
+		t.Errorf("expected [%v], got [%v]", expected, actual)
 	}
 
-	if actual, expected := g.SequenceMax(), max; actual != expected {
-		t.Errorf("expected [%d], got [%d]", expected, actual)
-	}
+	for _, meta := range []byte{0, 128, 254} {
+		id, err := g.TryNew(meta)
+		if err != nil {
+			t.Fatalf("expected no error for metabyte [%d], got [%v]", meta, err)
+		}
 
-	if actual, expected := g.Cap(), int(max-min)+1; actual != expected {
-		t.Errorf("expected [%d], got [%d]", expected, actual)
-	}
+		if actual, expected := id.Meta(), meta; actual != expected {
+			t.Errorf("expected [%d], got [%d]", expected, actual)
+		}
 
-	if actual, expected := g.Len(), 0; actual != expected {
-		t.Errorf("expected [%d], got [%d]", expected, actual)
+		if actual, expected := g.New(meta).Meta(), meta; actual != expected {
+			t.Errorf("expected [%d], got [%d]", expected, actual)
+		}
 	}
 
-	for i := 0; i < 5; i++ {
-		g.New(255)
+	id, err := g.TryNew(255)
+	if actual, expected := err, (&ReservedMetaError{Meta: 255}); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected [%v], got [%v]", expected, actual)
 	}
 
-	if actual, expected := g.Len(), 5; actual != expected {
-		t.Errorf("expected [%d], got [%d]", expected, actual)
+	if !id.IsZero() {
+		t.Errorf("expected zero ID, got [%s]", id)
 	}
 
-	g, err = NewGenerator(&GeneratorSnapshot{
-		SequenceMin: 8,
-		SequenceMax: 16,
-	}, nil)
-	if err != nil {
-		t.Fatal(err)
+	if _, err := g.NewWithUnixNano(255, time.Now().UnixNano()); err == nil {
+		t.Error("expected error, got none")
 	}
 
-	// Simulate an overflow. All IDs over Cap() must be generated in a subsequent timeframe
-	// meaning Len will reflect the count in the last frame.
-	// TODO(alcore) This *can* occasionally fail as we are not using a deterministic time source,
-	// meaning first batch can get split up if time changes during the test and then end up
-	// spilling into the Len() we test for.
-	for i := 0; i < g.Cap()+7; i++ {
-		g.New(255)
+	if actual, expected := (&ReservedMetaError{Meta: 255}).Error(), "sno: metabyte 255 is reserved"; actual != expected {
+		t.Errorf("expected [%s], got [%s]", expected, actual)
 	}
 
-	if actual, expected := g.Len(), 7; actual != expected {
-		t.Errorf("expected [%d], got [%d]", expected, actual)
-	}
+	for _, c := range []struct {
+		name string
+		fn   func()
+	}{
+		{"New", func() { g.New(255) }},
+		{"NewForPartition", func() { g.NewForPartition(255, Partition{}) }},
+		{"NewWithGap", func() { g.NewWithGap(255, 1) }},
+		{"NewWithTime", func() { g.NewWithTime(255, time.Now()) }},
+		{"NewBatchIndexed", func() { g.NewBatchIndexed(256) }},
+	} {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			defer func() {
+				if _, ok := recover().(*ReservedMetaError); !ok {
+					t.Errorf("expected a panic with [%T]", &ReservedMetaError{})
+				}
+			}()
 
-	g, err = NewGenerator(&GeneratorSnapshot{
-		SequenceMin: 8,
-		SequenceMax: 16,
-	}, nil)
-	if err != nil {
-		t.Fatal(err)
+			c.fn()
+		})
 	}
+}
 
-	for i := 0; i < g.Cap(); i++ {
-		g.New(255)
-	}
+func TestMetaSet_JSON(t *testing.T) {
+	for _, c := range []struct {
+		set  MetaSet
+		json string
+	}{
+		{MetaSet{}, `null`},
+		{MetaSet{0: true, 255: true}, `{"0":true,"255":true}`},
+	} {
+		data, err := json.Marshal(c.set)
+		if err != nil {
+			t.Fatal(err)
+		}
 
-	if actual, expected := g.Len(), g.Cap(); actual != expected {
-		t.Errorf("expected [%d], got [%d]", expected, actual)
+		if actual, expected := string(data), c.json; actual != expected {
+			t.Errorf("expected [%s], got [%s]", expected, actual)
+		}
+
+		var set MetaSet
+		if err := json.Unmarshal(data, &set); err != nil {
+			t.Fatal(err)
+		}
+
+		if actual, expected := set, c.set; actual != expected {
+			t.Errorf("expected [%v], got [%v]", expected, actual)
+		}
 	}
-}
 
-func TestGenerator_Sequence_Single(t *testing.T) {
-	g, err := NewGenerator(nil, nil)
-	if err != nil {
+	// Metabytes mapped to false (as the former map representation permitted) are not in the set.
+	var set MetaSet
+	if err := json.Unmarshal([]byte(`{"254":false,"255":true}`), &set); err != nil {
 		t.Fatal(err)
 	}
 
-	expected0 := uint32(0)
-	expected1 := expected0
-	expected2 := expected1 + 1
-	actual0 := g.Sequence()
-	_ = g.New(255)
-	actual1 := g.Sequence()
-	_ = g.New(255)
-	actual2 := g.Sequence()
-
-	if actual0 != expected0 {
-		t.Errorf("expected [%d], got [%d]", expected0, actual0)
-	}
-	if actual1 != expected1 {
-		t.Errorf("expected [%d], got [%d]", expected1, actual1)
-	}
-	if actual2 != expected2 {
-		t.Errorf("expected [%d], got [%d]", expected2, actual2)
+	if actual, expected := set, (MetaSet{255: true}); actual != expected {
+		t.Errorf("expected [%v], got [%v]", expected, actual)
 	}
 }
 
-func TestGenerator_Sequence_Batch(t *testing.T) {
-	g, err := NewGenerator(nil, nil)
+func TestGeneratorSnapshot_Comparable(t *testing.T) {
+	clock := manualClock(1000)
+
+	g, err := NewGeneratorWithOptions(&GeneratorSnapshot{
+		Partition:    Partition{255, 250},
+		ReservedMeta: MetaSet{255: true},
+	}, &GeneratorOptions{Clock: clock.now}, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	expected := uint32(9)
-	for i := 0; i <= int(expected); i++ {
-		_ = g.New(255)
+	g.New(0)
+
+	// Snapshots are plain values, so they can be compared (and used as map keys) as they are.
+	if a, b := g.Snapshot(), g.Snapshot(); a != b {
+		t.Errorf("expected [%+v] to equal [%+v]", a, b)
 	}
 
-	actual := g.Sequence()
-	if actual != expected {
-		t.Errorf("expected [%d], got [%d]", expected, actual)
+	a := g.Snapshot()
+	g.New(0)
+
+	if b := g.Snapshot(); a == b {
+		t.Errorf("expected [%+v] to differ from [%+v]", a, b)
 	}
 }
 
-func TestGenerator_FromSnapshot_Sequence(t *testing.T) {
-	seq := uint32(1024)
+func TestGenerator_NewWithTimes(t *testing.T) {
 	g, err := NewGenerator(&GeneratorSnapshot{
-		SequenceMin: uint16(seq),
-		Sequence:    seq,
+		Partition:   Partition{255, 250},
+		SequenceMin: 1024,
+		SequenceMax: 2047,
 	}, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	expected1 := seq
-	expected2 := seq + 1
-	_ = g.New(255)
-	actual1 := g.Sequence()
-	_ = g.New(255)
-	actual2 := g.Sequence()
+	var (
+		tn    = time.Now()
+		times = []time.Time{
+			tn,
+			tn,
+			tn.Add(time.Microsecond), // Same timeframe.
+			tn.Add(time.Second),
+			tn.Add(time.Second),
+			tn.Add(time.Hour),
+		}
+	)
 
-	if actual1 != expected1 {
-		t.Errorf("expected [%d], got [%d]", expected1, actual1)
+	ids, err := g.NewWithTimes(255, times)
+	if err != nil {
+		t.Fatal(err)
 	}
-	if actual2 != expected2 {
-		t.Errorf("expected [%d], got [%d]", expected2, actual2)
+
+	if actual, expected := len(ids), len(times); actual != expected {
+		t.Fatalf("expected [%d], got [%d]", expected, actual)
 	}
-}
 
-func TestGenerator_FromSnapshot_Pool_Defaults(t *testing.T) {
-	t.Parallel()
+	seen := make(map[ID]bool)
+	for i, id := range ids {
+		if actual, expected := id.Timestamp(), times[i].UnixNano()/TimeUnit*TimeUnit; actual != expected {
+			t.Errorf("%d: expected [%d], got [%d]", i, expected, actual)
+		}
+
+		if i > 0 && id.Compare(ids[i-1]) <= 0 {
+			t.Errorf("%d: expected [%s] to sort after [%s]", i, id, ids[i-1])
+		}
+
+		if seen[id] {
+			t.Errorf("%d: duplicate ID [%s]", i, id)
+		}
+		seen[id] = true
+	}
+
+	if actual, expected := ids[1].Sequence(), ids[0].Sequence()+1; actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	// Validation happens before any ID gets generated, so the sequence must not move on failure.
+	seq := atomic.LoadUint32(&g.seqStatic)
+	before := time.Date(2009, 12, 31, 0, 0, 0, 0, time.UTC)
+
+	ids, err = g.NewWithTimes(255, []time.Time{tn, tn, before})
+	if err == nil {
+		t.Fatal("expected error, got none")
+	}
+
+	if actual, ok := err.(*TimestampRangeError); !ok || !actual.Time.Equal(before) {
+		t.Errorf("expected a [%T] for [%s], got [%v]", actual, before, err)
+	}
+
+	if ids != nil {
+		t.Errorf("expected no IDs, got [%v]", ids)
+	}
+
+	if actual, expected := atomic.LoadUint32(&g.seqStatic), seq; actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	// Far enough in the future for UnixNano() to wrap around into the embeddable range.
+	after := time.Date(2600, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if _, err = g.NewWithTimes(255, []time.Time{after}); err == nil {
+		t.Fatal("expected error, got none")
+	}
+
+	if actual, ok := err.(*TimestampRangeError); !ok || !actual.Time.Equal(after) {
+		t.Errorf("expected a [%T] for [%s], got [%v]", actual, after, err)
+	}
+}
+
+func TestGenerator_NewWithCounter(t *testing.T) {
+	g, err := NewGenerator(&GeneratorSnapshot{
+		Partition:   Partition{255, 249},
+		SequenceMin: 1024,
+		SequenceMax: 2047,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snotime = staticTime
+	defer func() { snotime = internal.Snotime }()
+
+	var (
+		base = uint64(1<<40 + 65000)
+		n    = 4096
+		seen = make(map[ID]uint64, n)
+	)
+
+	// Spans a wraparound of the low 16 bits - all within the same timeframe.
+	for i := 0; i < n; i++ {
+		counter := base + uint64(i)
+		id := g.NewWithCounter(255, counter)
+
+		if prev, ok := seen[id]; ok {
+			t.Fatalf("counters [%d] and [%d] yielded the same ID [%s]", prev, counter, id)
+		}
+		seen[id] = counter
+
+		if actual, expected := id.Sequence(), uint16(counter); actual != expected {
+			t.Errorf("expected [%d], got [%d]", expected, actual)
+		}
+
+		if actual, expected := id.Partition(), g.Partition(); actual != expected {
+			t.Errorf("expected [%v], got [%v]", expected, actual)
+		}
+
+		if actual, expected := uint64(id.Timestamp()-epochNsec)/TimeUnit, atomic.LoadUint64(staticWallNow); actual != expected {
+			t.Errorf("expected [%d], got [%d]", expected, actual)
+		}
+	}
+
+	// The internal sequence remains untouched.
+	if actual, expected := g.Len(), 0; actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	// Counters equal modulo 65536 collide - which is the documented wraparound.
+	if a, b := g.NewWithCounter(255, base), g.NewWithCounter(255, base+65536); a != b {
+		t.Errorf("expected [%s] to equal [%s]", a, b)
+	}
+}
+
+func TestGenerator_NewForTenant(t *testing.T) {
+	g, err := NewGenerator(&GeneratorSnapshot{
+		Partition:   Partition{255, 248},
+		SequenceMin: 1024,
+		SequenceMax: 2047,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tenant := range []uint32{0, 1, 255, 256, 65535, 65536, 1<<23 + 1, MaxTenant} {
+		id, err := g.NewForTenant(tenant)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if actual, expected := id.Tenant(), tenant; actual != expected {
+			t.Errorf("expected [%d], got [%d]", expected, actual)
+		}
+
+		if actual, expected := id.Partition().AsUint16(), uint16(tenant); actual != expected {
+			t.Errorf("expected [%d], got [%d]", expected, actual)
+		}
+
+		if actual, expected := id.Meta(), byte(tenant>>16); actual != expected {
+			t.Errorf("expected [%d], got [%d]", expected, actual)
+		}
+
+		if seq := id.Sequence(); seq < 1024 || seq > 2047 {
+			t.Errorf("expected sequence in range [1024, 2047], got [%d]", seq)
+		}
+	}
+
+	id, err := g.NewForTenant(MaxTenant + 1)
+	if actual, expected := err, (&InvalidTenantError{Tenant: MaxTenant + 1}); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected [%v], got [%v]", expected, actual)
+	}
+
+	if !id.IsZero() {
+		t.Errorf("expected zero ID, got [%s]", id)
+	}
+
+	if actual, expected := err.Error(), "sno: tenant 16777216 exceeds the max tenant of 16777215"; actual != expected {
+		t.Errorf("expected [%s], got [%s]", expected, actual)
+	}
+}
+
+func TestGenerator_NewVersioned(t *testing.T) {
+	g, err := NewGenerator(&GeneratorSnapshot{
+		Partition:    Partition{'V', 'S'},
+		ReservedMeta: MetaSet{255: true},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []struct {
+		version uint8
+		payload uint8
+		meta    byte
+	}{
+		{0, 0, 0x00},
+		{1, 0, 0x20},
+		{5, 17, 0xB1},
+		{7, 30, 0xFE},
+		{7, 31, 0xFF},
+	} {
+		id, err := g.NewVersioned(c.version, c.payload)
+		if c.meta == 255 {
+			// Packs into the reserved metabyte.
+			if _, ok := err.(*ReservedMetaError); !ok {
+				t.Errorf("%d/%d: expected [%T], got [%v]", c.version, c.payload, &ReservedMetaError{}, err)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("%d/%d: %v", c.version, c.payload, err)
+		}
+
+		if actual, expected := id.Meta(), c.meta; actual != expected {
+			t.Errorf("%d/%d: expected [%#x], got [%#x]", c.version, c.payload, expected, actual)
+		}
+
+		if actual, expected := id.SchemaVersion(), c.version; actual != expected {
+			t.Errorf("%d/%d: expected version [%d], got [%d]", c.version, c.payload, expected, actual)
+		}
+
+		if actual, expected := id.SchemaPayload(), c.payload; actual != expected {
+			t.Errorf("%d/%d: expected payload [%d], got [%d]", c.version, c.payload, expected, actual)
+		}
+
+		if actual, expected := id.Partition(), (Partition{'V', 'S'}); actual != expected {
+			t.Errorf("%d/%d: expected [%v], got [%v]", c.version, c.payload, expected, actual)
+		}
+	}
+
+	for _, c := range []struct {
+		version uint8
+		payload uint8
+	}{
+		{8, 0},
+		{0, 32},
+		{255, 255},
+	} {
+		id, err := g.NewVersioned(c.version, c.payload)
+		if actual, expected := err, (&InvalidSchemaVersionError{Version: c.version, Payload: c.payload, Bits: 3}); !reflect.DeepEqual(actual, expected) {
+			t.Errorf("%d/%d: expected [%v], got [%v]", c.version, c.payload, expected, actual)
+		}
+
+		if !id.IsZero() {
+			t.Errorf("%d/%d: expected zero ID, got [%s]", c.version, c.payload, id)
+		}
+	}
+
+	if actual, expected := (&InvalidSchemaVersionError{Version: 8, Bits: 3}).Error(), "sno: schema version 8 and payload 0 do not fit a metabyte with 3 version bits"; actual != expected {
+		t.Errorf("expected [%s], got [%s]", expected, actual)
+	}
+}
+
+func TestGenerator_NewVersioned_SchemaVersionBits(t *testing.T) {
+	g, err := NewGenerator(&GeneratorSnapshot{
+		Partition:         Partition{'V', 'B'},
+		SchemaVersionBits: 5,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if actual, expected := g.Snapshot().SchemaVersionBits, uint8(5); actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	id, err := g.NewVersioned(22, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if actual, expected := id.Meta(), byte(22<<3|7); actual != expected {
+		t.Errorf("expected [%#x], got [%#x]", expected, actual)
+	}
+
+	if version, payload := id.SchemaSplit(5); version != 22 || payload != 7 {
+		t.Errorf("expected [22/7], got [%d/%d]", version, payload)
+	}
+
+	if _, err := g.NewVersioned(1, 8); !reflect.DeepEqual(err, &InvalidSchemaVersionError{Version: 1, Payload: 8, Bits: 5}) {
+		t.Errorf("expected [%v], got [%v]", &InvalidSchemaVersionError{Version: 1, Payload: 8, Bits: 5}, err)
+	}
+
+	// Defaults when not set.
+	if g, err = NewGenerator(&GeneratorSnapshot{Partition: Partition{'V', 'B'}}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if actual, expected := g.Snapshot().SchemaVersionBits, uint8(DefaultSchemaVersionBits); actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	_, err = NewGenerator(&GeneratorSnapshot{SchemaVersionBits: 9}, nil)
+	if actual, expected := err, (&InvalidSchemaVersionBitsError{Bits: 9}); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected [%v], got [%v]", expected, actual)
+	}
+}
+
+func TestGenerator_TryNew_FailFast(t *testing.T) {
+	g, err := NewGenerator(&GeneratorSnapshot{
+		Partition:   Partition{255, 247},
+		SequenceMin: 1024,
+		SequenceMax: 1039,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snotime = staticTime
+	defer func() { snotime = internal.Snotime }()
+
+	atomic.AddUint64(staticWallNow, 1)
+
+	for i := 0; i < g.Cap(); i++ {
+		if _, err := g.TryNew(255); err != nil {
+			t.Fatalf("%d: expected no error, got [%v]", i, err)
+		}
+	}
+
+	id, err := g.TryNew(255)
+	if actual, expected := err, (&SequencePoolExhaustedError{Sequence: 1040, Max: 1039}); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected [%v], got [%v]", expected, actual)
+	}
+
+	if !id.IsZero() {
+		t.Errorf("expected zero ID, got [%s]", id)
+	}
+
+	if actual, expected := g.TakeOverflowStats(), (OverflowStats{}); actual != expected {
+		t.Errorf("expected [%+v], got [%+v]", expected, actual)
+	}
+
+	// The next timeframe resets the pool.
+	atomic.AddUint64(staticWallNow, 1)
+
+	if id, err = g.TryNew(255); err != nil {
+		t.Fatalf("expected no error, got [%v]", err)
+	}
+
+	if actual, expected := id.Sequence(), uint16(1024); actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+}
+
+func TestGenerator_TryNew_MixedWithNew(t *testing.T) {
+	g, err := NewGenerator(&GeneratorSnapshot{
+		Partition:   Partition{255, 246},
+		SequenceMin: 1024,
+		SequenceMax: 1039,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		workers = 4
+		perCall = 8 * g.Cap()
+		mu      sync.Mutex
+		ids     = make(map[ID]bool, 2*workers*perCall)
+		fails   int
+		wg      sync.WaitGroup
+		collect = func(id ID) {
+			mu.Lock()
+			if ids[id] {
+				t.Errorf("duplicate ID [%s]", id)
+			}
+			ids[id] = true
+			mu.Unlock()
+		}
+	)
+
+	wg.Add(2 * workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < perCall; j++ {
+				collect(g.New(255))
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < perCall; j++ {
+				id, err := g.TryNew(255)
+				if err != nil {
+					if _, ok := err.(*SequencePoolExhaustedError); !ok {
+						t.Errorf("expected error type [%T], got [%T]", &SequencePoolExhaustedError{}, err)
+					}
+
+					mu.Lock()
+					fails++
+					mu.Unlock()
+
+					continue
+				}
+
+				collect(id)
+			}
+		}()
+	}
+
+	// Blocking callers must all get through eventually - regardless of what fail-fast callers did
+	// to the sequence in the meantime.
+	wg.Wait()
+
+	if actual, expected := len(ids)+fails, 2*workers*perCall; actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+}
+
+func TestGenerator_FailOnOverflow(t *testing.T) {
+	clock := manualClock(1000)
+
+	g, err := NewGeneratorWithOptions(&GeneratorSnapshot{
+		Partition:      Partition{255, 238},
+		SequenceMin:    1024,
+		SequenceMax:    1039,
+		FailOnOverflow: true,
+	}, &GeneratorOptions{Clock: clock.now}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if actual, expected := len(g.NewBatch(255, g.Cap())), g.Cap(); actual != expected {
+		t.Fatalf("expected [%d], got [%d]", expected, actual)
+	}
+
+	// Each call of the methods returning errors fails instead of blocking, and the sequence keeps going up.
+	if _, err := g.NewContext(context.Background(), 255); !reflect.DeepEqual(err, &SequencePoolExhaustedError{Sequence: 1040, Max: 1039}) {
+		t.Errorf("expected [%v], got [%v]", &SequencePoolExhaustedError{Sequence: 1040, Max: 1039}, err)
+	}
+
+	if _, err := g.NewForTenant(1); !reflect.DeepEqual(err, &SequencePoolExhaustedError{Sequence: 1041, Max: 1039}) {
+		t.Errorf("expected [%v], got [%v]", &SequencePoolExhaustedError{Sequence: 1041, Max: 1039}, err)
+	}
+
+	if _, err := g.NewVersioned(1, 1); !reflect.DeepEqual(err, &SequencePoolExhaustedError{Sequence: 1042, Max: 1039}) {
+		t.Errorf("expected [%v], got [%v]", &SequencePoolExhaustedError{Sequence: 1042, Max: 1039}, err)
+	}
+
+	if actual, expected := g.TakeOverflowStats(), (OverflowStats{}); actual != expected {
+		t.Errorf("expected [%+v], got [%+v]", expected, actual)
+	}
+
+	// The methods which can not return errors block as usual, instead of returning the zero ID.
+	released := make(chan ID)
+	go func() {
+		released <- g.New(255)
+	}()
+
+	select {
+	case id := <-released:
+		t.Fatalf("expected New to block, got [%s]", id)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	// The next timeframe resets the pool.
+	clock.set(1001)
+
+	select {
+	case id := <-released:
+		if actual, expected := id.Sequence(), uint16(1024); actual != expected {
+			t.Errorf("expected [%d], got [%d]", expected, actual)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected New to get released in the next timeframe")
+	}
+}
+
+func TestGenerator_FailOnOverflow_Snapshot(t *testing.T) {
+	g, err := NewGenerator(&GeneratorSnapshot{
+		Partition:      Partition{255, 237},
+		FailOnOverflow: true,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := g.Snapshot()
+	if actual, expected := s.FailOnOverflow, true; actual != expected {
+		t.Fatalf("expected [%t], got [%t]", expected, actual)
+	}
+
+	restored, err := NewGenerator(&s, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if actual, expected := restored.Snapshot().FailOnOverflow, true; actual != expected {
+		t.Errorf("expected [%t], got [%t]", expected, actual)
+	}
+}
+
+func TestGenerator_OverflowCallback(t *testing.T) {
+	var (
+		clock   = manualClock(1000)
+		mu      sync.Mutex
+		notifs  []SequenceOverflowNotification
+		declogs = make(chan struct{})
+	)
+
+	g, err := NewGeneratorWithOptions(&GeneratorSnapshot{
+		Name:        "callback",
+		Partition:   Partition{255, 236},
+		SequenceMin: 1024,
+		SequenceMax: 1039,
+	}, &GeneratorOptions{
+		Clock: clock.now,
+		OverflowFunc: func(n SequenceOverflowNotification) {
+			mu.Lock()
+			notifs = append(notifs, n)
+			mu.Unlock()
+
+			if n.Count == 0 {
+				close(declogs)
+			}
+		},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < g.Cap(); i++ {
+		g.New(255)
+	}
+
+	released := make(chan struct{})
+	go func() {
+		g.New(255)
+		close(released)
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	clock.set(1001)
+
+	select {
+	case <-declogs:
+	case <-time.After(time.Second):
+		t.Fatal("expected the callback to get notified of the declog")
+	}
+
+	<-released
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	// Roughly one tick per msec while overflowing.
+	if len(notifs) < 4 {
+		t.Fatalf("expected at least [4] notifications, got [%d]", len(notifs))
+	}
+
+	for i, n := range notifs {
+		if actual, expected := n.Ticks, uint32(i+1); actual != expected {
+			t.Errorf("%d: expected [%d], got [%d]", i, expected, actual)
+		}
+
+		if actual, expected := n.Name, "callback"; actual != expected {
+			t.Errorf("%d: expected [%s], got [%s]", i, expected, actual)
+		}
+	}
+
+	if actual, expected := notifs[0].Count, uint32(1); actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+}
+
+func TestGenerator_NewContext_CancelledMidOverflow(t *testing.T) {
+	g, err := NewGenerator(&GeneratorSnapshot{
+		Partition:   Partition{255, 245},
+		SequenceMin: 1024,
+		SequenceMax: 1039,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A static clock ahead of the wall clock never progresses - not even as seen by the overflow loop
+	// (which follows the actual wall clock) - so waiting callers stay parked until cancelled.
+	wall := atomic.LoadUint64(staticWallNow)
+	atomic.StoreUint64(staticWallNow, wall+1e6)
+	snotime = staticTime
+	defer func() {
+		snotime = internal.Snotime
+		atomic.StoreUint64(staticWallNow, wall)
+	}()
+
+	var (
+		baseline    = runtime.NumGoroutine()
+		ctx, cancel = context.WithCancel(context.Background())
+		waiters     = 8
+		errs        = make(chan error, waiters)
+		blocked     = make(chan ID, 1)
+	)
+	defer cancel()
+
+	for i := 0; i < g.Cap(); i++ {
+		if _, err := g.NewContext(ctx, 255); err != nil {
+			t.Fatalf("%d: expected no error, got [%v]", i, err)
+		}
+	}
+
+	for i := 0; i < waiters; i++ {
+		go func() {
+			_, err := g.NewContext(ctx, 255)
+			errs <- err
+		}()
+	}
+
+	// A blocking caller parked alongside them must remain unaffected by their cancellation.
+	go func() { blocked <- g.New(255) }()
+
+	waitForBlocked := func(n uint32) {
+		for {
+			g.seqOverflowCond.L.Lock()
+			count := g.seqOverflowCount
+			g.seqOverflowCond.L.Unlock()
+
+			if count == n {
+				return
+			}
+
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	waitForBlocked(uint32(waiters) + 1)
+	cancel()
+
+	for i := 0; i < waiters; i++ {
+		if actual, expected := <-errs, context.Canceled; actual != expected {
+			t.Errorf("expected [%v], got [%v]", expected, actual)
+		}
+	}
+
+	waitForBlocked(1)
+
+	select {
+	case id := <-blocked:
+		t.Fatalf("expected the blocking caller to remain blocked, got [%s]", id)
+	default:
+	}
+
+	// Progressing to the next timeframe releases the blocking caller.
+	atomic.AddUint64(staticWallNow, 1)
+
+	if actual, expected := g.New(255).Sequence(), uint16(1024); actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	if actual, expected := (<-blocked).Sequence(), uint16(1025); actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	// Once the overflow loop notices it has declogged, nothing may be left running.
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > baseline {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected [%d] goroutines, got [%d]", baseline, runtime.NumGoroutine())
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestGenerator_NewContext_DeadlineExceeded(t *testing.T) {
+	g, err := NewGenerator(&GeneratorSnapshot{
+		Partition:   Partition{255, 244},
+		SequenceMin: 1024,
+		SequenceMax: 1039,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wall := atomic.LoadUint64(staticWallNow)
+	atomic.StoreUint64(staticWallNow, wall+1e6)
+	snotime = staticTime
+	defer func() {
+		snotime = internal.Snotime
+		atomic.StoreUint64(staticWallNow, wall)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+
+	var errs int
+	for i := 0; i < g.Cap()+1; i++ {
+		if _, err := g.NewContext(ctx, 255); err != nil {
+			if actual, expected := err, context.DeadlineExceeded; actual != expected {
+				t.Errorf("expected [%v], got [%v]", expected, actual)
+			}
+
+			errs++
+		}
+	}
+
+	if actual, expected := errs, 1; actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+}
+
+func TestGenerator_NewWithTimeOverflows(t *testing.T) {
+	var (
+		part         = Partition{255, 255}
+		seqPool      = 12
+		seqOverflows = 4
+		seqMin       = uint16(seqPool)
+		seqMax       = uint16(2*seqPool - 1)
+		sampleSize   = seqPool * seqOverflows
+
+		g, err = NewGenerator(&GeneratorSnapshot{
+			Partition:   part,
+			SequenceMin: seqMin,
+			SequenceMax: seqMax,
+		}, nil)
+	)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tn := time.Now()
+	pool := g.Cap()
+
+	ids := make([]ID, sampleSize)
+	for i := 0; i < sampleSize; i++ {
+		ids[i] = g.NewWithTime(byte(i), tn)
+	}
+
+	timeDist := make(map[int64]int)
+
+	for i, s := 0, 0; i < sampleSize; i, s = i+1, s+1 {
+		id := ids[i]
+		timeDist[id.Timestamp()]++
+
+		seq := id.Sequence()
+		if seq > seqMax {
+			t.Errorf("%d: sequence overflowing max boundary; max [%d], got [%d]", i, seqMin, seq)
+		}
+
+		if seq < seqMin {
+			t.Errorf("%d: sequence underflowing min boundary; min [%d], got [%d]", i, seqMin, seq)
+		}
+
+		// When we overflow with NewWithTime, the static sequence is supposed to roll over silently.
+		if s == pool {
+			s = 0
+		} else if i > 0 && seq-ids[i-1].Sequence() != 1 {
+			t.Errorf("%d: expected sequence to increment by 1, got [%d]", i, seq-ids[i-1].Sequence())
+		}
+
+		expectedSeq := uint16(s) + seqMin
+		if seq != expectedSeq {
+			t.Errorf("%d: expected sequence [%d], got [%d]", i, expectedSeq, seq)
+		}
+
+		if id.Partition() != part {
+			t.Errorf("%d: partition differs from generator's partition; expected [%d], got [%d]", i, part, id.Partition())
+		}
+	}
+
+	if len(timeDist) > 1 {
+		t.Error("IDs generated with the same time ended up with different timestamps")
+	}
+
+	// Race test.
+	var wg sync.WaitGroup
+	wg.Add(1000)
+	for i := 0; i < 1000; i++ {
+		go func() {
+			for i := 0; i < sampleSize; i++ {
+				_ = g.NewWithTime(byte(i), tn)
+			}
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+}
+
+func TestGenerator_Uniqueness(t *testing.T) {
+	var (
+		collisions int
+		setSize    = 4 * MaxSequence
+	)
+
+	ids := make(map[ID]struct{}, setSize)
+
+	for i := 1; i < setSize; i++ {
+		id := defaultGenerator().New(255)
+		if _, found := ids[id]; found {
+			collisions++
+		} else {
+			ids[id] = struct{}{}
+		}
+	}
+
+	if collisions > 0 {
+		t.Errorf("generated %d colliding IDs in a set of %d", collisions, setSize)
+	}
+}
+
+func TestGenerator_Partition(t *testing.T) {
+	expected := Partition{'A', 255}
+	g, err := NewGenerator(&GeneratorSnapshot{
+		Partition: expected,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual := g.Partition()
+	if actual != expected {
+		t.Errorf("expected [%s], got [%s]", expected, actual)
+	}
+}
+
+func TestGenerator_SequenceBounds(t *testing.T) {
+	min := uint16(1024)
+	max := uint16(2047)
+	g, err := NewGenerator(&GeneratorSnapshot{
+		SequenceMin: min,
+		SequenceMax: max,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if actual, expected := g.SequenceMin(), min; actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	if actual, expected := g.SequenceMax(), max; actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	if actual, expected := g.Cap(), int(max-min)+1; actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	if actual, expected := g.Len(), 0; actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	for i := 0; i < 5; i++ {
+		g.New(255)
+	}
+
+	if actual, expected := g.Len(), 5; actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	g, err = NewGenerator(&GeneratorSnapshot{
+		SequenceMin: 8,
+		SequenceMax: 16,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate an overflow. All IDs over Cap() must be generated in a subsequent timeframe
+	// meaning Len will reflect the count in the last frame.
+	// TODO(alcore) This *can* occasionally fail as we are not using a deterministic time source,
+	// meaning first batch can get split up if time changes during the test and then end up
+	// spilling into the Len() we test for.
+	for i := 0; i < g.Cap()+7; i++ {
+		g.New(255)
+	}
+
+	if actual, expected := g.Len(), 7; actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	g, err = NewGenerator(&GeneratorSnapshot{
+		SequenceMin: 8,
+		SequenceMax: 16,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < g.Cap(); i++ {
+		g.New(255)
+	}
+
+	if actual, expected := g.Len(), g.Cap(); actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+}
+
+func TestGenerator_Sequence_Single(t *testing.T) {
+	g, err := NewGenerator(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected0 := uint32(0)
+	expected1 := expected0
+	expected2 := expected1 + 1
+	actual0 := g.Sequence()
+	_ = g.New(255)
+	actual1 := g.Sequence()
+	_ = g.New(255)
+	actual2 := g.Sequence()
+
+	if actual0 != expected0 {
+		t.Errorf("expected [%d], got [%d]", expected0, actual0)
+	}
+	if actual1 != expected1 {
+		t.Errorf("expected [%d], got [%d]", expected1, actual1)
+	}
+	if actual2 != expected2 {
+		t.Errorf("expected [%d], got [%d]", expected2, actual2)
+	}
+}
+
+func TestGenerator_Sequence_Batch(t *testing.T) {
+	g, err := NewGenerator(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := uint32(9)
+	for i := 0; i <= int(expected); i++ {
+		_ = g.New(255)
+	}
+
+	actual := g.Sequence()
+	if actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+}
+
+func TestGenerator_FromSnapshot_Sequence(t *testing.T) {
+	seq := uint32(1024)
+	g, err := NewGenerator(&GeneratorSnapshot{
+		SequenceMin: uint16(seq),
+		Sequence:    seq,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected1 := seq
+	expected2 := seq + 1
+	_ = g.New(255)
+	actual1 := g.Sequence()
+	_ = g.New(255)
+	actual2 := g.Sequence()
+
+	if actual1 != expected1 {
+		t.Errorf("expected [%d], got [%d]", expected1, actual1)
+	}
+	if actual2 != expected2 {
+		t.Errorf("expected [%d], got [%d]", expected2, actual2)
+	}
+}
+
+func TestGenerator_FromSnapshot_Pool_Defaults(t *testing.T) {
+	t.Parallel()
+
+	g, err := NewGenerator(&GeneratorSnapshot{
+		SequenceMin: 0,
+		SequenceMax: 0,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g.SequenceMin() != 0 {
+		t.Errorf("expected [%d], got [%d]", 0, g.SequenceMin())
+	}
+
+	if g.SequenceMax() != MaxSequence {
+		t.Errorf("expected [%d], got [%d]", MaxSequence, g.SequenceMax())
+	}
+
+	// Max as default when min is given.
+	g, err = NewGenerator(&GeneratorSnapshot{
+		SequenceMin: 2048,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g.SequenceMin() != 2048 {
+		t.Errorf("expected [%d], got [%d]", 2048, g.SequenceMin())
+	}
+
+	if g.SequenceMax() != MaxSequence {
+		t.Errorf("expected [%d], got [%d]", MaxSequence, g.SequenceMax())
+	}
+}
+
+func TestGenerator_FromSnapshot_Pool_BoundsOrder(t *testing.T) {
+	t.Parallel()
+
+	g, err := NewGenerator(&GeneratorSnapshot{
+		SequenceMin: 2048,
+		SequenceMax: 1024,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g.SequenceMin() != 1024 {
+		t.Errorf("expected [%d], got [%d]", 1024, g.SequenceMin())
+	}
+
+	if g.SequenceMax() != 2048 {
+		t.Errorf("expected [%d], got [%d]", 2048, g.SequenceMax())
+	}
+}
+
+func TestGenerator_FromSnapshot_Pool_None(t *testing.T) {
+	t.Parallel()
+
+	bound := uint16(2048)
+	_, err := NewGenerator(&GeneratorSnapshot{
+		SequenceMin: bound,
+		SequenceMax: bound,
+	}, nil)
+	if err == nil {
+		t.Errorf("expected error, got none")
+		return
+	}
+
+	verr, ok := err.(*InvalidSequenceBoundsError)
+	if !ok {
+		t.Errorf("expected error type [%T], got [%T]", &InvalidSequenceBoundsError{}, err)
+		return
+	}
+
+	if verr.Msg != errSequenceBoundsIdenticalMsg {
+		t.Errorf("expected error msg [%s], got [%s]", errSequenceBoundsIdenticalMsg, verr.Msg)
+	}
+
+	if verr.Min != bound {
+		t.Errorf("expected [%d], got [%d]", bound, verr.Min)
+	}
+
+	if verr.Max != bound {
+		t.Errorf("expected [%d], got [%d]", bound, verr.Max)
+	}
+
+	expectedMsg := fmt.Sprintf(errInvalidSequenceBoundsFmt, errSequenceBoundsIdenticalMsg, bound, 0, bound, 1)
+	if verr.Error() != expectedMsg {
+		t.Errorf("expected error msg [%s], got [%s]", expectedMsg, verr.Error())
+	}
+}
+
+func TestGenerator_FromSnapshot_Pool_Size(t *testing.T) {
+	t.Parallel()
+
+	seqMin := uint16(0)
+	seqMax := seqMin + minSequencePoolSize - 1
+	_, err := NewGenerator(&GeneratorSnapshot{
+		SequenceMin: seqMin,
+		SequenceMax: seqMax,
+	}, nil)
+	if err == nil {
+		t.Errorf("expected error, got none")
+		return
+	}
+
+	verr, ok := err.(*InvalidSequenceBoundsError)
+	if !ok {
+		t.Errorf("expected error type [%T], got [%T]", &InvalidSequenceBoundsError{}, err)
+		return
+	}
+
+	if verr.Msg != errSequencePoolTooSmallMsg {
+		t.Errorf("expected error msg [%s], got [%s]", errSequencePoolTooSmallMsg, verr.Msg)
+	}
+
+	if verr.Min != seqMin {
+		t.Errorf("expected [%d], got [%d]", seqMin, verr.Min)
+	}
+
+	if verr.Max != seqMax {
+		t.Errorf("expected [%d], got [%d]", seqMax, verr.Max)
+	}
+
+	expectedMsg := fmt.Sprintf(errInvalidSequenceBoundsFmt, errSequencePoolTooSmallMsg, seqMin, 0, seqMax, seqMax-seqMin+1)
+	if verr.Error() != expectedMsg {
+		t.Errorf("expected error msg [%s], got [%s]", expectedMsg, verr.Error())
+	}
+}
+
+func TestGenerator_FromSnapshot_Underflow(t *testing.T) {
+	t.Parallel()
+
+	seqMin := uint16(2048)
+	seq := uint32(seqMin - 1)
+	_, err := NewGenerator(&GeneratorSnapshot{
+		SequenceMin: seqMin,
+		Sequence:    seq,
+	}, nil)
+	if err == nil {
+		t.Errorf("expected error, got none")
+		return
+	}
+
+	verr, ok := err.(*InvalidSequenceBoundsError)
+	if !ok {
+		t.Errorf("expected error type [%T], got [%T]", &InvalidSequenceBoundsError{}, err)
+		return
+	}
+
+	if verr.Msg != errSequenceUnderflowsBound {
+		t.Errorf("expected error msg [%s], got [%s]", errSequenceUnderflowsBound, verr.Msg)
+	}
+
+	if verr.Min != seqMin {
+		t.Errorf("expected [%d], got [%d]", seqMin, verr.Min)
+	}
+
+	if verr.Cur != seq {
+		t.Errorf("expected [%d], got [%d]", seq, verr.Cur)
+	}
+
+	expectedMsg := fmt.Sprintf(errInvalidSequenceBoundsFmt, errSequenceUnderflowsBound, seqMin, seq, MaxSequence, MaxSequence-seqMin+1)
+	if verr.Error() != expectedMsg {
+		t.Errorf("expected error msg [%s], got [%s]", expectedMsg, verr.Error())
+	}
+}
+
+func TestGenerator_OneShotPerTimeframe(t *testing.T) {
+	wall := internal.Snotime()
+
+	snotime = staticTime
+	atomic.StoreUint64(staticWallNow, wall)
+
+	g, err := NewGenerator(&GeneratorSnapshot{
+		SequenceMin:         16,
+		SequenceMax:         31,
+		OneShotPerTimeframe: true,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := g.New(255)
+
+	// Way past the capacity of the pool - but the generator must neither overflow nor advance.
+	for i := 0; i < 4*g.Cap(); i++ {
+		if actual, expected := g.New(255), first; actual != expected {
+			t.Fatalf("%d: expected [%s], got [%s]", i, expected, actual)
+		}
+	}
+
+	if actual, expected := first.Sequence(), uint16(16); actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	atomic.AddUint64(staticWallNow, 1)
+
+	next := g.New(255)
+	if next == first {
+		t.Error("expected a different ID in the next timeframe")
+	}
+
+	if actual, expected := next.Sequence(), uint16(16); actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	if actual, expected := g.Snapshot().OneShotPerTimeframe, true; actual != expected {
+		t.Errorf("expected [%t], got [%t]", expected, actual)
+	}
+
+	snotime = internal.Snotime
+}
+
+func TestGenerator_NewFromContent(t *testing.T) {
+	wall := internal.Snotime()
+
+	snotime = staticTime
+	atomic.StoreUint64(staticWallNow, wall)
+
+	g, err := NewGenerator(&GeneratorSnapshot{
+		Partition: Partition{'C', 'C'},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		a = g.NewFromContent([]byte("content"))
+		b = g.NewFromContent([]byte("content"))
+		c = g.NewFromContent([]byte("different content"))
+	)
+
+	if a != b {
+		t.Errorf("expected [%s] and [%s] to be identical within a timeframe", a, b)
+	}
+
+	if a == c {
+		t.Errorf("expected IDs for different content to differ, got [%s] twice", a)
+	}
+
+	if actual, expected := a.Partition(), (Partition{'C', 'C'}); actual != expected {
+		t.Errorf("expected [%v], got [%v]", expected, actual)
+	}
+
+	if actual, expected := a.Timestamp(), int64(wall)*TimeUnit+epochNsec; actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	atomic.AddUint64(staticWallNow, 1)
+
+	next := g.NewFromContent([]byte("content"))
+	if next == a {
+		t.Error("expected a different ID in the next timeframe")
+	}
+
+	if a.Sub(next).Timeframes != -1 || a.Meta() != next.Meta() || a.Sequence() != next.Sequence() {
+		t.Errorf("expected [%s] to only differ from [%s] by its timestamp", next, a)
+	}
+
+	snotime = internal.Snotime
+}
+
+func TestGenerator_NewForPartition(t *testing.T) {
+	wall := internal.Snotime()
+
+	snotime = staticTime
+	atomic.StoreUint64(staticWallNow, wall)
+
+	g, err := NewGenerator(&GeneratorSnapshot{
+		Partition:   Partition{'P', 'P'},
+		SequenceMin: 0,
+		SequenceMax: 1023,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		parts = []Partition{{0, 0}, {0, 1}, {'P', 'P'}}
+		seen  = make(map[ID]struct{})
+		seqs  = make(map[uint16]struct{})
+	)
+
+	for i := 0; i < 300; i++ {
+		p := parts[i%len(parts)]
+
+		var id ID
+		if i%2 == 0 {
+			id = g.NewForPartition(255, p)
+		} else {
+			// Interleave with IDs for the Generator's own partition, which share the sequence.
+			id, p = g.New(255), g.Partition()
+		}
+
+		if actual, expected := id.Partition(), p; actual != expected {
+			t.Errorf("expected [%v], got [%v]", expected, actual)
+		}
+
+		if _, ok := seen[id]; ok {
+			t.Errorf("duplicate ID [%s]", id)
+		}
+
+		if _, ok := seqs[id.Sequence()]; ok {
+			t.Errorf("duplicate sequence [%d] within the timeframe", id.Sequence())
+		}
+
+		if actual, expected := id.Timestamp(), int64(wall)*TimeUnit+epochNsec; actual != expected {
+			t.Errorf("expected [%d], got [%d]", expected, actual)
+		}
+
+		seen[id] = struct{}{}
+		seqs[id.Sequence()] = struct{}{}
+	}
+
+	snotime = internal.Snotime
+}
+
+func TestGenerator_NewWithGap(t *testing.T) {
+	wall := internal.Snotime()
+
+	snotime = staticTime
+	atomic.StoreUint64(staticWallNow, wall)
 
 	g, err := NewGenerator(&GeneratorSnapshot{
-		SequenceMin: 0,
-		SequenceMax: 0,
+		SequenceMin: 100,
+		SequenceMax: 199,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The first ID of a timeframe gets the gap as well.
+	if actual, expected := g.NewWithGap(255, 2).Sequence(), uint16(102); actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	for i, c := range []struct {
+		skip uint16
+		seq  uint16
+	}{
+		{0, 103},
+		{3, 107},
+		{0, 108},
+		{10, 119},
+	} {
+		if actual, expected := g.NewWithGap(255, c.skip).Sequence(), c.seq; actual != expected {
+			t.Errorf("%d: expected [%d], got [%d]", i, expected, actual)
+		}
+	}
+
+	if actual, expected := g.New(255).Sequence(), uint16(120); actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	// Skips exceeding the capacity get clamped.
+	atomic.AddUint64(staticWallNow, 1)
+
+	if actual, expected := g.NewWithGap(255, MaxSequence).Sequence(), uint16(199); actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	snotime = internal.Snotime
+}
+
+func TestGenerator_ReserveNext(t *testing.T) {
+	g, err := NewGenerator(&GeneratorSnapshot{
+		Partition:   Partition{255, 240},
+		SequenceMin: 1024,
+		SequenceMax: 1039,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Concurrent callers must not be able to interfere with the sequence each of them gets back.
+	var (
+		workers = 4
+		wg      sync.WaitGroup
+	)
+
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 4*g.Cap(); j++ {
+				id, seq := g.ReserveNext(255)
+				if actual, expected := id.Sequence(), seq; actual != expected {
+					t.Errorf("expected [%d], got [%d]", expected, actual)
+				}
+
+				if seq < 1024 || seq > 1039 {
+					t.Errorf("expected sequence within [1024, 1039], got [%d]", seq)
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+func TestGenerator_NewSpaced(t *testing.T) {
+	g, err := NewGenerator(&GeneratorSnapshot{
+		Partition: Partition{255, 237},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		workers = 3
+		perCall = 8
+		mu      sync.Mutex
+		ids     []ID
+		wg      sync.WaitGroup
+	)
+
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < perCall; j++ {
+				id := g.NewSpaced(255)
+
+				// Regular IDs interleaved from the same goroutine don't affect the spacing.
+				g.New(255)
+
+				mu.Lock()
+				ids = append(ids, id)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	frames := make(map[int64]ID, len(ids))
+	for _, id := range ids {
+		if prev, ok := frames[id.Timestamp()]; ok {
+			t.Errorf("expected [%s] and [%s] to be in distinct timeframes", prev, id)
+		}
+
+		frames[id.Timestamp()] = id
+	}
+
+	if actual, expected := len(frames), workers*perCall; actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+}
+
+func TestGenerator_NewBatch(t *testing.T) {
+	g, err := NewGenerator(&GeneratorSnapshot{
+		Partition:   Partition{255, 243},
+		SequenceMin: 1024,
+		SequenceMax: 1039,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snotime = staticTime
+	defer func() { snotime = internal.Snotime }()
+
+	atomic.AddUint64(staticWallNow, 1)
+
+	first := g.New(255)
+
+	// The remainder of the pool gets reserved as a single block.
+	ids := g.NewBatch(255, g.Cap()-1)
+
+	if actual, expected := len(ids), g.Cap()-1; actual != expected {
+		t.Fatalf("expected [%d], got [%d]", expected, actual)
+	}
+
+	for i, id := range ids {
+		if actual, expected := id.Sequence(), uint16(1025+i); actual != expected {
+			t.Errorf("%d: expected [%d], got [%d]", i, expected, actual)
+		}
+
+		if actual, expected := id.Timestamp(), first.Timestamp(); actual != expected {
+			t.Errorf("%d: expected [%d], got [%d]", i, expected, actual)
+		}
+	}
+
+	if _, err := g.TryNew(255); err == nil {
+		t.Errorf("expected the pool to be exhausted")
+	}
+
+	if ids := g.NewBatch(255, 0); ids != nil {
+		t.Errorf("expected [nil], got [%v]", ids)
+	}
+}
+
+func TestGenerator_NewBatch_Rollover(t *testing.T) {
+	g, err := NewGenerator(&GeneratorSnapshot{
+		Partition:   Partition{255, 242},
+		SequenceMin: 1024,
+		SequenceMax: 1039,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := g.NewBatch(255, 5*g.Cap()+3)
+
+	if actual, expected := len(ids), 5*g.Cap()+3; actual != expected {
+		t.Fatalf("expected [%d], got [%d]", expected, actual)
+	}
+
+	frames := 1
+	for i := 1; i < len(ids); i++ {
+		if ids[i].Compare(ids[i-1]) <= 0 {
+			t.Errorf("%d: expected [%s] to sort after [%s]", i, ids[i], ids[i-1])
+		}
+
+		if ids[i].Timestamp() != ids[i-1].Timestamp() {
+			frames++
+		}
+	}
+
+	if frames < 6 {
+		t.Errorf("expected the batch to span at least [6] timeframes, got [%d]", frames)
+	}
+}
+
+func TestGenerator_NewBatch_MixedWithNew(t *testing.T) {
+	g, err := NewGenerator(&GeneratorSnapshot{
+		Partition:   Partition{255, 241},
+		SequenceMin: 1024,
+		SequenceMax: 1087,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		workers = 4
+		perCall = 4 * g.Cap()
+		mu      sync.Mutex
+		ids     = make(map[ID]bool, 2*workers*perCall)
+		wg      sync.WaitGroup
+		collect = func(id ID) {
+			mu.Lock()
+			if ids[id] {
+				t.Errorf("duplicate ID [%s]", id)
+			}
+			ids[id] = true
+			mu.Unlock()
+		}
+	)
+
+	wg.Add(2 * workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < perCall; j++ {
+				collect(g.New(255))
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < perCall; j += 24 {
+				batch := g.NewBatch(255, 24)
+				for k, id := range batch {
+					if k > 0 && id.Compare(batch[k-1]) <= 0 {
+						t.Errorf("expected [%s] to sort after [%s]", id, batch[k-1])
+					}
+
+					collect(id)
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if actual, expected := len(ids), workers*perCall+workers*((perCall+23)/24*24); actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+}
+
+func TestGenerator_NewBatchIndexed(t *testing.T) {
+	g, err := NewGenerator(&GeneratorSnapshot{
+		Partition:   Partition{255, 235},
+		SequenceMin: 1024,
+		SequenceMax: 1055,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Spans several timeframes, so that the batch gets assembled from several blocks.
+	ids := g.NewBatchIndexed(256)
+
+	if actual, expected := len(ids), 256; actual != expected {
+		t.Fatalf("expected [%d], got [%d]", expected, actual)
+	}
+
+	shuffled := make([]ID, len(ids))
+	copy(shuffled, ids)
+	rand.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+
+	recovered := make([]ID, len(ids))
+	for _, id := range shuffled {
+		recovered[id.Meta()] = id
+	}
+
+	for i := range ids {
+		if actual, expected := recovered[i], ids[i]; actual != expected {
+			t.Errorf("%d: expected [%s], got [%s]", i, expected, actual)
+		}
+
+		if i > 0 && recovered[i].Compare(recovered[i-1]) <= 0 {
+			t.Errorf("%d: expected [%s] to sort after [%s]", i, recovered[i], recovered[i-1])
+		}
+	}
+
+	if ids := g.NewBatchIndexed(0); ids != nil {
+		t.Errorf("expected [nil], got [%v]", ids)
+	}
+
+	defer func() {
+		err, ok := recover().(*InvalidBatchSizeError)
+		if !ok {
+			t.Fatalf("expected a panic with [%T]", &InvalidBatchSizeError{})
+		}
+
+		if actual, expected := err.Error(), "sno: batch size 257 exceeds the max of 256"; actual != expected {
+			t.Errorf("expected [%s], got [%s]", expected, actual)
+		}
+	}()
+
+	g.NewBatchIndexed(257)
+}
+
+func TestGenerator_ReadOnly(t *testing.T) {
+	g, err := NewGenerator(&GeneratorSnapshot{
+		Partition:   Partition{'R', 'O'},
+		SequenceMin: 100,
+		SequenceMax: 199,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var v interface{} = g.ReadOnly()
+
+	// Neither generation nor a way back to the Generator must be available through the view.
+	if _, ok := v.(interface{ New(byte) ID }); ok {
+		t.Error("expected view to not expose New()")
+	}
+
+	if _, ok := v.(*Generator); ok {
+		t.Error("expected view to not be convertible back into the Generator")
+	}
+
+	view := g.ReadOnly()
+	g.New(255)
+
+	if actual, expected := view.Partition(), g.Partition(); actual != expected {
+		t.Errorf("expected [%v], got [%v]", expected, actual)
+	}
+
+	if view.SequenceMin() != 100 || view.SequenceMax() != 199 || view.Cap() != 100 {
+		t.Errorf("expected bounds [100, 199] and capacity [100], got [%d, %d] and [%d]",
+			view.SequenceMin(), view.SequenceMax(), view.Cap())
+	}
+
+	if actual, expected := view.Len(), g.Len(); actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	if actual, expected := view.Sequence(), g.Sequence(); actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	if actual, expected := view.Snapshot().Partition, g.Snapshot().Partition; actual != expected {
+		t.Errorf("expected [%v], got [%v]", expected, actual)
+	}
+}
+
+func TestSpaceOverlap(t *testing.T) {
+	for _, c := range []struct {
+		name    string
+		a, b    GeneratorSnapshot
+		overlap float64
+	}{
+		{
+			"different-partition",
+			GeneratorSnapshot{Partition: Partition{0, 1}, SequenceMin: 0, SequenceMax: 99},
+			GeneratorSnapshot{Partition: Partition{0, 2}, SequenceMin: 0, SequenceMax: 99},
+			0,
+		},
+		{
+			"same-partition-disjoint",
+			GeneratorSnapshot{Partition: Partition{0, 1}, SequenceMin: 0, SequenceMax: 99},
+			GeneratorSnapshot{Partition: Partition{0, 1}, SequenceMin: 100, SequenceMax: 199},
+			0,
+		},
+		{
+			"same-partition-overlapping",
+			GeneratorSnapshot{Partition: Partition{0, 1}, SequenceMin: 0, SequenceMax: 99},
+			GeneratorSnapshot{Partition: Partition{0, 1}, SequenceMin: 50, SequenceMax: 149},
+			50.0 / 150,
+		},
+		{
+			"same-partition-contained",
+			GeneratorSnapshot{Partition: Partition{0, 1}, SequenceMin: 0, SequenceMax: 99},
+			GeneratorSnapshot{Partition: Partition{0, 1}, SequenceMin: 25, SequenceMax: 49},
+			25.0 / 100,
+		},
+		{
+			"identical",
+			GeneratorSnapshot{Partition: Partition{0, 1}, SequenceMin: 0, SequenceMax: 99},
+			GeneratorSnapshot{Partition: Partition{0, 1}, SequenceMin: 0, SequenceMax: 99},
+			1,
+		},
+	} {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			a, err := NewGenerator(&c.a, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			b, err := NewGenerator(&c.b, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// The measure is symmetric.
+			for _, actual := range []float64{SpaceOverlap(a, b), SpaceOverlap(b, a)} {
+				if actual != c.overlap {
+					t.Errorf("expected [%v], got [%v]", c.overlap, actual)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerator_CompatibleWith(t *testing.T) {
+	for _, c := range []struct {
+		name   string
+		a, b   GeneratorSnapshot
+		ok     bool
+		reason string
+	}{
+		{
+			"different-partition",
+			GeneratorSnapshot{Partition: Partition{0, 1}, SequenceMin: 0, SequenceMax: 99},
+			GeneratorSnapshot{Partition: Partition{0, 2}, SequenceMin: 0, SequenceMax: 99},
+			true, "",
+		},
+		{
+			"same-partition-disjoint",
+			GeneratorSnapshot{Partition: Partition{0, 1}, SequenceMin: 0, SequenceMax: 99},
+			GeneratorSnapshot{Partition: Partition{0, 1}, SequenceMin: 100, SequenceMax: 199},
+			true, "",
+		},
+		{
+			"same-partition-overlapping",
+			GeneratorSnapshot{Partition: Partition{0, 1}, SequenceMin: 0, SequenceMax: 100},
+			GeneratorSnapshot{Partition: Partition{0, 1}, SequenceMin: 100, SequenceMax: 199},
+			false, "generators share partition 1 and their sequence bounds [0, 100] and [100, 199] overlap",
+		},
+	} {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			a, err := NewGenerator(&c.a, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			b, err := NewGenerator(&c.b, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ok, reason := a.CompatibleWith(b)
+			if ok != c.ok {
+				t.Errorf("expected [%t], got [%t]", c.ok, ok)
+			}
+
+			if reason != c.reason {
+				t.Errorf("expected [%s], got [%s]", c.reason, reason)
+			}
+
+			// Compatibility is symmetric.
+			if reversed, _ := b.CompatibleWith(a); reversed != ok {
+				t.Errorf("expected [%t] in reverse, got [%t]", ok, reversed)
+			}
+		})
+	}
+}
+
+func TestGenerator_NewWithTimeByte(t *testing.T) {
+	wall := internal.Snotime()
+
+	snotime = staticTime
+
+	g, err := NewGenerator(&GeneratorSnapshot{
+		Partition: Partition{'T', 'B'},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Cover more than 256 seconds (at 1 second = 250 time units) for the byte to wrap.
+	for i := uint64(0); i < 300; i++ {
+		atomic.StoreUint64(staticWallNow, wall+i*250+i%250)
+
+		id := g.NewWithTimeByte()
+		if actual, expected := id.Meta(), byte(id.Time().Unix()); actual != expected {
+			t.Fatalf("%d: expected [%d], got [%d]", i, expected, actual)
+		}
+	}
+
+	snotime = internal.Snotime
+}
+
+func TestGenerator_Jitter(t *testing.T) {
+	wall := internal.Snotime()
+
+	snotime = staticTime
+
+	g, err := NewGenerator(&GeneratorSnapshot{
+		SequenceMin: 100,
+		SequenceMax: 199,
+		Jitter:      10,
 	}, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	if g.SequenceMin() != 0 {
-		t.Errorf("expected [%d], got [%d]", 0, g.SequenceMin())
-	}
+	starts := make(map[uint16]int)
 
-	if g.SequenceMax() != MaxSequence {
-		t.Errorf("expected [%d], got [%d]", MaxSequence, g.SequenceMax())
+	for i := uint64(0); i < 512; i++ {
+		atomic.StoreUint64(staticWallNow, wall+i)
+
+		seq := g.New(255).Sequence()
+		if seq < 100 || seq > 110 {
+			t.Fatalf("%d: expected sequence within [100, 110], got [%d]", i, seq)
+		}
+
+		starts[seq]++
+
+		// Within the timeframe, the sequence simply increments.
+		if actual, expected := g.New(255).Sequence(), seq+1; actual != expected {
+			t.Fatalf("%d: expected [%d], got [%d]", i, expected, actual)
+		}
 	}
 
-	// Max as default when min is given.
-	g, err = NewGenerator(&GeneratorSnapshot{
-		SequenceMin: 2048,
-	}, nil)
-	if err != nil {
-		t.Fatal(err)
+	if len(starts) < 8 {
+		t.Errorf("expected jitter to spread across the range, got [%v]", starts)
 	}
 
-	if g.SequenceMin() != 2048 {
-		t.Errorf("expected [%d], got [%d]", 2048, g.SequenceMin())
+	snotime = internal.Snotime
+
+	// Jitter must leave room for at least minSequencePoolSize IDs.
+	_, err = NewGenerator(&GeneratorSnapshot{
+		SequenceMin: 100,
+		SequenceMax: 199,
+		Jitter:      97,
+	}, nil)
+	if _, ok := err.(*InvalidSequenceBoundsError); !ok {
+		t.Fatalf("expected error type [%T], got [%T]", &InvalidSequenceBoundsError{}, err)
 	}
 
-	if g.SequenceMax() != MaxSequence {
-		t.Errorf("expected [%d], got [%d]", MaxSequence, g.SequenceMax())
+	if actual, expected := err.(*InvalidSequenceBoundsError).Msg, errSequenceJitterTooLargeMsg; actual != expected {
+		t.Errorf("expected [%s], got [%s]", expected, actual)
 	}
 }
 
-func TestGenerator_FromSnapshot_Pool_BoundsOrder(t *testing.T) {
-	t.Parallel()
+func TestGenerator_Jitter_OneShot(t *testing.T) {
+	wall := internal.Snotime()
+
+	snotime = staticTime
 
 	g, err := NewGenerator(&GeneratorSnapshot{
-		SequenceMin: 2048,
-		SequenceMax: 1024,
+		SequenceMin:         100,
+		SequenceMax:         199,
+		Jitter:              10,
+		OneShotPerTimeframe: true,
 	}, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	if g.SequenceMin() != 1024 {
-		t.Errorf("expected [%d], got [%d]", 1024, g.SequenceMin())
+	for i := uint64(0); i < 64; i++ {
+		atomic.StoreUint64(staticWallNow, wall+i)
+
+		// The first ID of each timeframe must be the same one that gets repeated within it.
+		first := g.New(255)
+		if actual, expected := first.Sequence(), uint16(100); actual != expected {
+			t.Fatalf("%d: expected [%d], got [%d]", i, expected, actual)
+		}
+
+		if actual, expected := g.New(255), first; actual != expected {
+			t.Fatalf("%d: expected [%s], got [%s]", i, expected, actual)
+		}
 	}
 
-	if g.SequenceMax() != 2048 {
-		t.Errorf("expected [%d], got [%d]", 2048, g.SequenceMax())
+	if actual, expected := g.Snapshot().Jitter, uint16(10); actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
 	}
-}
 
-func TestGenerator_FromSnapshot_Pool_None(t *testing.T) {
-	t.Parallel()
+	snotime = internal.Snotime
+}
 
-	bound := uint16(2048)
-	_, err := NewGenerator(&GeneratorSnapshot{
-		SequenceMin: bound,
-		SequenceMax: bound,
-	}, nil)
-	if err == nil {
-		t.Errorf("expected error, got none")
-		return
+func TestGenerator_HasOverflowChannel(t *testing.T) {
+	g, err := NewGenerator(nil, nil)
+	if err != nil {
+		t.Fatal(err)
 	}
 
-	verr, ok := err.(*InvalidSequenceBoundsError)
-	if !ok {
-		t.Errorf("expected error type [%T], got [%T]", &InvalidSequenceBoundsError{}, err)
-		return
+	if g.HasOverflowChannel() {
+		t.Error("expected no overflow channel to be reported")
 	}
 
-	if verr.Msg != errSequenceBoundsIdenticalMsg {
-		t.Errorf("expected error msg [%s], got [%s]", errSequenceBoundsIdenticalMsg, verr.Msg)
+	g, err = NewGenerator(nil, make(chan *SequenceOverflowNotification))
+	if err != nil {
+		t.Fatal(err)
 	}
 
-	if verr.Min != bound {
-		t.Errorf("expected [%d], got [%d]", bound, verr.Min)
+	if !g.HasOverflowChannel() {
+		t.Error("expected an overflow channel to be reported")
 	}
+}
 
-	if verr.Max != bound {
-		t.Errorf("expected [%d], got [%d]", bound, verr.Max)
-	}
+func TestGenerator_ValidatePartitioning(t *testing.T) {
+	for _, c := range []struct {
+		name   string
+		in     [][2]uint16
+		err    error
+		errMsg string
+	}{
+		{"empty", nil, nil, ""},
+		{"disjoint", [][2]uint16{{0, 99}, {200, 299}, {MaxSequence - 99, MaxSequence}}, nil, ""},
+		{"adjacent", [][2]uint16{{100, 199}, {0, 99}, {200, 299}}, nil, ""},
+		{"reversed", [][2]uint16{{99, 0}, {199, 100}}, nil, ""},
+		{
+			"overlapping",
+			[][2]uint16{{0, 99}, {200, 299}, {150, 99}},
+			&SequenceRangesOverlapError{},
+			"sno: sequence ranges overlap; [0, 99] and [99, 150]",
+		},
+		{
+			"undersized",
+			[][2]uint16{{0, 99}, {100, 103}},
+			&InvalidSequenceBoundsError{},
+			fmt.Sprintf(errInvalidSequenceBoundsFmt, errSequencePoolTooSmallMsg, 100, 100, 103, 4),
+		},
+		{
+			"identical",
+			[][2]uint16{{100, 100}},
+			&InvalidSequenceBoundsError{},
+			fmt.Sprintf(errInvalidSequenceBoundsFmt, errSequenceBoundsIdenticalMsg, 100, 100, 100, 1),
+		},
+	} {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			err := ValidatePartitioning(c.in)
+
+			if actual, expected := reflect.TypeOf(err), reflect.TypeOf(c.err); actual != expected {
+				t.Fatalf("expected error type [%v], got [%v]", expected, actual)
+			}
 
-	expectedMsg := fmt.Sprintf(errInvalidSequenceBoundsFmt, errSequenceBoundsIdenticalMsg, bound, 0, bound, 1)
-	if verr.Error() != expectedMsg {
-		t.Errorf("expected error msg [%s], got [%s]", expectedMsg, verr.Error())
+			if err != nil && err.Error() != c.errMsg {
+				t.Errorf("expected error message [%s], got [%s]", c.errMsg, err.Error())
+			}
+		})
 	}
 }
 
-func TestGenerator_FromSnapshot_Pool_Size(t *testing.T) {
-	t.Parallel()
+func TestGenerator_Epoch(t *testing.T) {
+	const epoch = 1577836800 // 2020-01-01 00:00:00 UTC.
 
-	seqMin := uint16(0)
-	seqMax := seqMin + minSequencePoolSize - 1
-	_, err := NewGenerator(&GeneratorSnapshot{
-		SequenceMin: seqMin,
-		SequenceMax: seqMax,
+	g, err := NewGenerator(&GeneratorSnapshot{
+		Partition: Partition{'E', 'P'},
+		Epoch:     epoch,
 	}, nil)
-	if err == nil {
-		t.Errorf("expected error, got none")
-		return
+	if err != nil {
+		t.Fatal(err)
 	}
 
-	verr, ok := err.(*InvalidSequenceBoundsError)
-	if !ok {
-		t.Errorf("expected error type [%T], got [%T]", &InvalidSequenceBoundsError{}, err)
-		return
+	if actual, expected := g.Epoch(), int64(epoch); actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
 	}
 
-	if verr.Msg != errSequencePoolTooSmallMsg {
-		t.Errorf("expected error msg [%s], got [%s]", errSequencePoolTooSmallMsg, verr.Msg)
-	}
+	var (
+		before = time.Now()
+		id     = g.New(255)
+		after  = time.Now()
+	)
 
-	if verr.Min != seqMin {
-		t.Errorf("expected [%d], got [%d]", seqMin, verr.Min)
+	// Read back relative to the custom epoch, the time is the current one.
+	if tm := id.TimeWithEpoch(g.Epoch()); tm.Before(before.Add(-TimeUnit)) || tm.After(after) {
+		t.Errorf("expected [%s] to be within [%s, %s]", tm, before, after)
 	}
 
-	if verr.Max != seqMax {
-		t.Errorf("expected [%d], got [%d]", seqMax, verr.Max)
+	// Read back relative to the default epoch, the time is shifted by the difference between the epochs.
+	if actual, expected := id.TimeWithEpoch(g.Epoch()).Sub(id.Time()), time.Duration(epoch-Epoch)*time.Second; actual != expected {
+		t.Errorf("expected [%s], got [%s]", expected, actual)
 	}
 
-	expectedMsg := fmt.Sprintf(errInvalidSequenceBoundsFmt, errSequencePoolTooSmallMsg, seqMin, 0, seqMax, seqMax-seqMin+1)
-	if verr.Error() != expectedMsg {
-		t.Errorf("expected error msg [%s], got [%s]", expectedMsg, verr.Error())
+	// Times given by the caller are relative to the custom epoch as well.
+	tn := time.Date(2021, 6, 1, 12, 0, 0, 7e6, time.UTC)
+	if actual, expected := g.NewWithTime(255, tn).TimeWithEpoch(epoch), tn.Truncate(TimeUnit); !actual.Equal(expected) {
+		t.Errorf("expected [%s], got [%s]", expected, actual)
 	}
-}
 
-func TestGenerator_FromSnapshot_Underflow(t *testing.T) {
-	t.Parallel()
+	if _, err := g.NewWithUnixNano(255, time.Date(2019, 12, 31, 0, 0, 0, 0, time.UTC).UnixNano()); err == nil {
+		t.Errorf("expected a TimestampRangeError for a time before the custom epoch")
+	}
 
-	seqMin := uint16(2048)
-	seq := uint32(seqMin - 1)
-	_, err := NewGenerator(&GeneratorSnapshot{
-		SequenceMin: seqMin,
-		Sequence:    seq,
+	// The epoch survives snapshots.
+	r, err := NewGenerator(&GeneratorSnapshot{
+		Partition: Partition{'E', 'P'},
+		Epoch:     g.Snapshot().Epoch,
 	}, nil)
-	if err == nil {
-		t.Errorf("expected error, got none")
-		return
+	if err != nil {
+		t.Fatal(err)
 	}
 
-	verr, ok := err.(*InvalidSequenceBoundsError)
-	if !ok {
-		t.Errorf("expected error type [%T], got [%T]", &InvalidSequenceBoundsError{}, err)
-		return
+	if actual, expected := r.Epoch(), int64(epoch); actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
 	}
 
-	if verr.Msg != errSequenceUnderflowsBound {
-		t.Errorf("expected error msg [%s], got [%s]", errSequenceUnderflowsBound, verr.Msg)
+	// Defaults to the package's epoch.
+	d, err := NewGenerator(nil, nil)
+	if err != nil {
+		t.Fatal(err)
 	}
 
-	if verr.Min != seqMin {
-		t.Errorf("expected [%d], got [%d]", seqMin, verr.Min)
+	if actual, expected := d.Epoch(), int64(Epoch); actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
 	}
+}
 
-	if verr.Cur != seq {
-		t.Errorf("expected [%d], got [%d]", seq, verr.Cur)
-	}
+func TestGenerator_Epoch_Invalid(t *testing.T) {
+	now := time.Now().Unix()
+
+	for _, c := range []struct {
+		name  string
+		epoch int64
+	}{
+		{"future", now + 3600},
+		{"past", now - MaxTimestamp/250 - 3600},
+	} {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			g, err := NewGenerator(&GeneratorSnapshot{
+				Partition: Partition{'E', 'P'},
+				Epoch:     c.epoch,
+			}, nil)
+			if g != nil {
+				t.Errorf("expected [nil], got [%v]", g)
+			}
 
-	expectedMsg := fmt.Sprintf(errInvalidSequenceBoundsFmt, errSequenceUnderflowsBound, seqMin, seq, MaxSequence, MaxSequence-seqMin+1)
-	if verr.Error() != expectedMsg {
-		t.Errorf("expected error msg [%s], got [%s]", expectedMsg, verr.Error())
+			if actual, expected := err, (&InvalidEpochError{Epoch: c.epoch}); !reflect.DeepEqual(actual, expected) {
+				t.Errorf("expected [%v], got [%v]", expected, actual)
+			}
+		})
 	}
 }
 
@@ -921,3 +2896,287 @@ func TestGenerator_Snapshot(t *testing.T) {
 		t.Errorf("expected [%d], got [%d]", seqMax, actual.SequenceMax)
 	}
 }
+
+func TestGenerator_LogicalClock(t *testing.T) {
+	var (
+		seqMin = uint16(1024)
+		seqMax = uint16(1039)
+		frames = 8
+		g, err = NewGenerator(&GeneratorSnapshot{
+			SequenceMin:  seqMin,
+			SequenceMax:  seqMax,
+			LogicalClock: true,
+		}, nil)
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wall := internal.Snotime()
+	atomic.StoreUint64(staticWallNow, wall)
+	snotime = staticTime
+
+	// With a frozen clock, a regular generator would block on the first overflow - we are expecting
+	// the logical clock to progress by one time unit per exhausted pool instead.
+	ids := make([]ID, frames*g.Cap())
+	for i := range ids {
+		ids[i] = g.New(255)
+	}
+
+	for i := 1; i < len(ids); i++ {
+		if ids[i].Compare(ids[i-1]) <= 0 {
+			t.Fatalf("%d: expected ID to sort after the previous one", i)
+		}
+	}
+
+	for i, id := range ids {
+		expectedUnits := int64(wall) + int64(i/g.Cap())
+		if actual, expected := id.Timestamp(), expectedUnits*TimeUnit+epochNsec; actual != expected {
+			t.Fatalf("%d: expected [%d], got [%d]", i, expected, actual)
+		}
+
+		if actual, expected := id.Sequence(), seqMin+uint16(i%g.Cap()); actual != expected {
+			t.Fatalf("%d: expected [%d], got [%d]", i, expected, actual)
+		}
+	}
+
+	// Wall clock regression (relative to the logical clock) must not cause a tick-tock nor a regression
+	// of the timestamps.
+	atomic.StoreUint64(staticWallNow, wall-1)
+
+	id := g.New(255)
+	if id.Compare(ids[len(ids)-1]) <= 0 {
+		t.Error("expected ID to sort after the previous one")
+	}
+
+	if id[4]&1 != 0 {
+		t.Error("expected tick-tock bit to not be set, was set")
+	}
+
+	if actual := atomic.LoadUint32(&g.drifts); actual != 0 {
+		t.Errorf("expected [0] drifts recorded, got [%d]", actual)
+	}
+
+	// Once the wall clock catches up and moves ahead, the logical clock follows it.
+	atomic.StoreUint64(staticWallNow, wall+uint64(frames)+10)
+
+	if actual, expected := g.New(255).Timestamp(), int64(wall+uint64(frames)+10)*TimeUnit+epochNsec; actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	snotime = internal.Snotime
+}
+
+func TestGenerator_Floor(t *testing.T) {
+	var (
+		wall  = internal.Snotime()
+		floor uint64
+		saves int
+		save  = func(w uint64) {
+			if w <= floor {
+				t.Errorf("expected saved floor to increase past [%d], got [%d]", floor, w)
+			}
+
+			floor = w
+			saves++
+		}
+		load = func() uint64 {
+			return floor
+		}
+	)
+
+	snotime = staticTime
+	atomic.StoreUint64(staticWallNow, wall)
+
+	g, err := NewGeneratorWithOptions(&GeneratorSnapshot{}, &GeneratorOptions{SaveFloor: save, LoadFloor: load}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var last ID
+	for i := 0; i < 10; i++ {
+		atomic.StoreUint64(staticWallNow, wall+uint64(i))
+
+		for j := 0; j < 3; j++ {
+			last = g.New(255)
+		}
+	}
+
+	// One save per timeframe advance, not per ID.
+	if actual, expected := saves, 10; actual != expected {
+		t.Errorf("expected [%d] saves, got [%d]", expected, actual)
+	}
+
+	if actual, expected := floor, wall+9; actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	// Simulate a crash (no snapshot gets taken) followed by a restart with the wall clock reset
+	// to a time before the IDs we already handed out.
+	t.Run("blocking", func(t *testing.T) {
+		atomic.StoreUint64(staticWallNow, wall-100)
+
+		g, err := NewGeneratorWithOptions(&GeneratorSnapshot{}, &GeneratorOptions{SaveFloor: save, LoadFloor: load}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		out := make(chan ID)
+		go func() {
+			out <- g.New(255)
+		}()
+
+		select {
+		case <-out:
+			t.Fatal("expected New() to block while the wall clock is behind the floor")
+		case <-time.After(10 * time.Millisecond):
+		}
+
+		atomic.StoreUint64(staticWallNow, wall+10)
+
+		id := <-out
+		if id.Compare(last) <= 0 {
+			t.Errorf("expected ID [%s] to sort after [%s]", id, last)
+		}
+
+		if actual, expected := id.Timestamp(), int64(wall+10)*TimeUnit+epochNsec; actual != expected {
+			t.Errorf("expected [%d], got [%d]", expected, actual)
+		}
+	})
+
+	t.Run("logical", func(t *testing.T) {
+		atomic.StoreUint64(staticWallNow, wall-100)
+
+		g, err := NewGeneratorWithOptions(&GeneratorSnapshot{
+			LogicalClock: true,
+		}, &GeneratorOptions{SaveFloor: save, LoadFloor: load}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		prev := last
+		for i := 0; i < 10; i++ {
+			id := g.New(255)
+			if id.Compare(prev) <= 0 {
+				t.Errorf("expected ID [%s] to sort after [%s]", id, prev)
+			}
+
+			prev = id
+		}
+	})
+
+	snotime = internal.Snotime
+}
+
+func TestGenerator_MinFirstID(t *testing.T) {
+	clock := manualClock(20000)
+
+	prev, err := NewGeneratorWithOptions(&GeneratorSnapshot{
+		Partition: Partition{'M', 'F'},
+	}, &GeneratorOptions{Clock: clock.now}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var last ID
+	for i := 0; i < 16; i++ {
+		last = prev.New(255)
+	}
+
+	for _, c := range []struct {
+		name    string
+		wall    uint64
+		logical bool
+		units   uint64
+	}{
+		{"ahead", 20200, false, 20200},
+		{"behind-logical", 19900, true, 20001},
+		{"same-logical", 20000, true, 20001},
+	} {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			clock := manualClock(c.wall)
+
+			// A different partition and a lower metabyte, so that only the timestamp can make it sort after.
+			g, err := NewGeneratorWithOptions(&GeneratorSnapshot{
+				Partition:    Partition{'M', 'A'},
+				LogicalClock: c.logical,
+				MinFirstID:   &last,
+			}, &GeneratorOptions{Clock: clock.now}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			id := g.New(0)
+			if id.Compare(last) <= 0 {
+				t.Errorf("expected [%s] to sort after [%s]", id, last)
+			}
+
+			if actual, expected := id.Timestamp(), int64(c.units)*TimeUnit+epochNsec; actual != expected {
+				t.Errorf("expected [%d], got [%d]", expected, actual)
+			}
+		})
+	}
+
+	t.Run("blocking", func(t *testing.T) {
+		clock := manualClock(20000)
+
+		g, err := NewGeneratorWithOptions(&GeneratorSnapshot{
+			Partition:  Partition{'M', 'A'},
+			MinFirstID: &last,
+		}, &GeneratorOptions{Clock: clock.now}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		out := make(chan ID)
+		go func() {
+			out <- g.New(0)
+		}()
+
+		select {
+		case <-out:
+			t.Fatal("expected New() to block while the clock is within the timeframe of MinFirstID")
+		case <-time.After(10 * time.Millisecond):
+		}
+
+		clock.set(20001)
+
+		if id := <-out; id.Compare(last) <= 0 {
+			t.Errorf("expected [%s] to sort after [%s]", id, last)
+		}
+	})
+
+	t.Run("implausible", func(t *testing.T) {
+		for _, c := range []struct {
+			wall uint64
+			err  bool
+		}{
+			{20000 - 59*250, false},
+			{20000 - 61*250, true},
+		} {
+			clock := manualClock(c.wall)
+
+			_, err := NewGeneratorWithOptions(&GeneratorSnapshot{
+				MinFirstID: &last,
+			}, &GeneratorOptions{Clock: clock.now}, nil)
+
+			if !c.err {
+				if err != nil {
+					t.Errorf("expected no error, got [%v]", err)
+				}
+
+				continue
+			}
+
+			verr, ok := err.(*InvalidMinFirstIDError)
+			if !ok {
+				t.Fatalf("expected error type [%T], got [%T]", &InvalidMinFirstIDError{}, err)
+			}
+
+			if actual, expected := verr.Lead, 61*time.Second; actual != expected {
+				t.Errorf("expected [%s], got [%s]", expected, actual)
+			}
+		}
+	})
+}
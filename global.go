@@ -1,7 +1,12 @@
 package sno
 
 import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
 	"sort"
+	"strconv"
+	"sync/atomic"
 	"time"
 	"unsafe"
 
@@ -9,7 +14,7 @@ import (
 )
 
 var (
-	generator *Generator
+	generator atomic.Value // *Generator. Swapped by Configure.
 	zero      ID
 )
 
@@ -17,19 +22,66 @@ func init() {
 	doInit()
 }
 
+// DefaultPartition (optional) bakes the Partition of the package-level generator into the binary,
+// e.g. for deployments with one immutable binary per shard. It is meant to be set at build time
+// via the linker, as a uint16 in base 10:
+//	go build -ldflags "-X github.com/muyo/sno.DefaultPartition=1234"
+//
+// When empty (the default), the package-level generator gets a Partition based on time, as usual.
+// The value gets parsed during package initialization, which panics with an InvalidDefaultPartitionError
+// if it is not valid. Changing it at runtime has no effect - see Configure instead.
+var DefaultPartition string
+
 func doInit() {
-	g, err := NewGenerator(nil, nil)
+	var snapshot *GeneratorSnapshot
+
+	if DefaultPartition != "" {
+		p, err := strconv.ParseUint(DefaultPartition, 10, 16)
+		if err != nil {
+			panic(&InvalidDefaultPartitionError{Value: DefaultPartition})
+		}
+
+		snapshot = &GeneratorSnapshot{}
+		snapshot.Partition.PutUint16(uint16(p))
+	}
+
+	g, err := NewGenerator(snapshot, nil)
 	if err != nil {
 		panic(err)
 	}
 
-	generator = g
+	generator.Store(g)
+}
+
+// defaultGenerator returns the current package-level generator.
+func defaultGenerator() *Generator {
+	return generator.Load().(*Generator)
+}
+
+// Configure replaces the package-level generator with one constructed from the given snapshot
+// (see NewGenerator), e.g. to have the package-level New and NewWithTime generate IDs within
+// a managed Partition. If the snapshot is invalid, the error gets returned and the package-level
+// generator remains unchanged.
+//
+// Configure is safe for concurrent use - the generator gets swapped atomically, so calls to the package-level
+// functions that generate IDs use either the previous or the new generator. It is still meant to be called
+// early in main(), before the first use of those functions, as IDs generated by the previous generator
+// may collide with those of the new one, if their Partitions and sequence bounds overlap.
+func Configure(snapshot *GeneratorSnapshot) error {
+	g, err := NewGenerator(snapshot, nil)
+	if err != nil {
+		return err
+	}
+
+	generator.Store(g)
+
+	return nil
 }
 
 // New uses the package-level generator to generate a new ID using the current system
 // time for its timestamp.
 func New(meta byte) ID {
-	return generator.New(meta)
+	return defaultGenerator().New(meta)
 }
 
 // NewWithTime uses the package-level generator to generate a new ID using the given time
@@ -38,7 +90,121 @@ func New(meta byte) ID {
 // IDs generated using this method are subject to several caveats.
 // See generator.NewWithTime() for their documentation.
 func NewWithTime(meta byte, t time.Time) ID {
-	return generator.NewWithTime(meta, t)
+	return defaultGenerator().NewWithTime(meta, t)
+}
+
+// ComposeUnixNano composes an ID out of the given components, with the given time, expressed in
+// nanoseconds since the Unix epoch, as its timestamp - without involving a Generator, e.g. for
+// interop with systems that carry their own partitioning and sequencing.
+//
+// Returns a TimestampRangeError if the time falls before our epoch or after the max embeddable timestamp.
+func ComposeUnixNano(unixNano int64, p Partition, meta byte, seq uint16) (id ID, err error) {
+	units, err := unixNanoToUnits(unixNano, epochNsec)
+	if err != nil {
+		return zero, err
+	}
+
+	return compose(units, p, meta, seq), nil
+}
+
+// NewID composes an ID out of the given components, with the given time as its timestamp (and the
+// tick-tock bit unset) - without involving a Generator, e.g. for tests or for migrating IDs from other
+// schemes. Unlike NewWithTime, it takes the partition and the sequence explicitly.
+//
+// Times which can not be embedded get clamped: times before our epoch to the zero timestamp and times
+// after TimestampExhaustionDate to MaxTimestamp. Use NewIDChecked to have them rejected instead.
+//
+// Nothing guarantees the uniqueness of the ID - that is up to the caller.
+func NewID(t time.Time, meta byte, p Partition, seq uint16) ID {
+	units := timeToUnits(t)
+	if units < 0 {
+		units = 0
+	} else if units > MaxTimestamp {
+		units = MaxTimestamp
+	}
+
+	return compose(uint64(units), p, meta, seq)
+}
+
+// NewIDChecked composes an ID out of the given components like NewID, but returns a TimestampRangeError
+// if the time falls before our epoch or after the max embeddable timestamp, instead of clamping it.
+func NewIDChecked(t time.Time, meta byte, p Partition, seq uint16) (ID, error) {
+	units := timeToUnits(t)
+	if units < 0 || units > MaxTimestamp {
+		return zero, timestampRangeError(t, units)
+	}
+
+	return compose(uint64(units), p, meta, seq), nil
+}
+
+// timeToUnits translates the given time into a timestamp in sno time units, which may be out of
+// the embeddable range.
+func timeToUnits(t time.Time) int64 {
+	// Computed via seconds, since t.UnixNano() is undefined for times far enough from the Unix epoch.
+	return (t.Unix()-Epoch)*250 + int64(t.Nanosecond())/TimeUnit
+}
+
+// compose assembles an ID out of the given components, with the given timestamp (in sno time units,
+// which must be embeddable) and the tick-tock bit unset.
+func compose(units uint64, p Partition, meta byte, seq uint16) (id ID) {
+	binary.BigEndian.PutUint64(id[:], units<<25)
+	id[5] = meta
+	id[6] = p[0]
+	id[7] = p[1]
+	binary.BigEndian.PutUint16(id[8:], seq)
+
+	return
+}
+
+// AtTime returns the ID with the given time as its timestamp and a zero payload (no metabyte,
+// partition nor sequence), e.g. for use as the lower bound of a range query for IDs of any partition.
+//
+// It is the smallest possible ID within the timeframe of the given time (which gets truncated
+// to a 4msec resolution) - tick-tocked IDs of that timeframe included.
+//
+// Times which can not be embedded get clamped like in NewID: times before our epoch to the zero
+// timestamp and times after TimestampExhaustionDate to MaxTimestamp - so that range queries bounded
+// by them still cover what they would be expected to. Use AtTimeChecked to have them rejected instead.
+func AtTime(t time.Time) ID {
+	return NewID(t, 0, Partition{}, 0)
+}
+
+// AtTimeChecked returns the ID with the given time as its timestamp and a zero payload like AtTime,
+// but returns a TimestampRangeError if the time falls before our epoch or after the max embeddable
+// timestamp, instead of clamping it.
+func AtTimeChecked(t time.Time) (ID, error) {
+	return NewIDChecked(t, 0, Partition{}, 0)
+}
+
+// TimestampExhaustionDate returns the last time which can be embedded in the timestamp of an ID
+// (MaxTimestamp), i.e. 2079-09-07 15:47:35.548 UTC. Generators can not generate IDs past it.
+func TimestampExhaustionDate() time.Time {
+	return time.Unix(0, MaxTimestamp*TimeUnit+epochNsec).UTC()
+}
+
+// MaxLifetime returns how long a Generator could keep generating IDs at the given sustained rate,
+// starting now, before exhausting the timestamp space (see TimestampExhaustionDate).
+//
+// For rates a Generator can serve within its timeframes - up to MaxSequence+1 IDs per TimeUnit,
+// i.e. 16384000 IDs per second with the full sequence pool - the lifetime does not depend on the rate
+// and simply lasts until the exhaustion date. Beyond that, Generators block their callers instead,
+// except for those with a LogicalClock, which advance their clock ahead of the wall clock and therefore
+// exhaust the timestamp space proportionally sooner - which is what gets returned for such rates.
+//
+// Returns 0 if the exhaustion date has passed.
+func MaxLifetime(idsPerSecond int) time.Duration {
+	const perSecond = (MaxSequence + 1) * (1e9 / TimeUnit)
+
+	d := time.Until(TimestampExhaustionDate())
+	if d <= 0 {
+		return 0
+	}
+
+	if idsPerSecond > perSecond {
+		d = time.Duration(float64(d) * perSecond / float64(idsPerSecond))
+	}
+
+	return d
 }
 
 // FromBinaryBytes takes a byte slice and copies its contents into an ID, returning the bytes as an ID.
@@ -48,6 +214,14 @@ func FromBinaryBytes(src []byte) (id ID, err error) {
 	return id, id.UnmarshalBinary(src)
 }
 
+// FromLittleEndian restores an ID from its little-endian form, as returned by ID.LittleEndian.
+func FromLittleEndian(b [SizeBinary]byte) (id ID) {
+	id[0], id[1], id[2], id[3], id[4] = b[4], b[3], b[2], b[1], b[0]
+	id[5], id[6], id[7], id[8], id[9] = b[9], b[8], b[7], b[6], b[5]
+
+	return
+}
+
 // FromEncodedBytes decodes a canonically base32-encoded byte slice representation of an ID
 // into its binary representation and returns it.
 //
@@ -57,7 +231,8 @@ func FromEncodedBytes(src []byte) (id ID, err error) {
 }
 
 // FromEncodedString decodes a canonically base32-encoded string representation of an ID
-// into its binary representation and returns it.
+// into its binary representation and returns it. It does not validate the characters of the string -
+// see FromEncodedStringStrict.
 //
 // The string must have a length of 16. Returns a InvalidDataSizeError if it does not.
 func FromEncodedString(src string) (id ID, err error) {
@@ -69,6 +244,396 @@ func FromEncodedString(src string) (id ID, err error) {
 	return internal.Decode(*(*[]byte)(unsafe.Pointer(&src))), nil
 }
 
+// FromEncodedBytesStrict decodes a canonically base32-encoded byte slice representation of an ID
+// like FromEncodedBytes, but validates it first - see FromEncodedStringStrict.
+func FromEncodedBytesStrict(src []byte) (id ID, err error) {
+	if len(src) != SizeEncoded {
+		return zero, &InvalidDataSizeError{Size: len(src)}
+	}
+
+	if i := internal.IndexInvalid(src); i >= 0 {
+		return zero, &InvalidEncodingError{Byte: src[i], Index: i}
+	}
+
+	return internal.Decode(src), nil
+}
+
+// FromEncodedStringStrict decodes a canonically base32-encoded string representation of an ID
+// like FromEncodedString, but validates it first - e.g. for untrusted input.
+//
+// Decoding itself does not validate its input and maps characters outside of the alphabet to garbage
+// IDs (without errors), which keeps it fast for data which is known to be sound. In contrast, this
+// checks that each character is within the alphabet ([2-9a-x]) and returns an InvalidEncodingError
+// naming the first one which is not.
+//
+// The string must have a length of 16. Returns a InvalidDataSizeError if it does not.
+func FromEncodedStringStrict(src string) (id ID, err error) {
+	// We only read in the data pointer (and input is read-only), so this does the job.
+	return FromEncodedBytesStrict(*(*[]byte)(unsafe.Pointer(&src)))
+}
+
+// FromEncodedStringInsensitive decodes a base32-encoded string representation of an ID regardless
+// of the case of its letters - e.g. as returned by ID.StringUpper or after passing through systems
+// which do not preserve case - and validates it like FromEncodedStringStrict.
+//
+// The string gets folded to lower case before decoding, so decoding itself (including its vectorized
+// implementation) remains the same as for canonical strings.
+//
+// The string must have a length of 16. Returns a InvalidDataSizeError if it does not and an
+// InvalidEncodingError naming the first character which is not within the alphabet in either case.
+func FromEncodedStringInsensitive(src string) (id ID, err error) {
+	if len(src) != SizeEncoded {
+		return zero, &InvalidDataSizeError{Size: len(src)}
+	}
+
+	var buf [SizeEncoded]byte
+	for i := 0; i < SizeEncoded; i++ {
+		if c := src[i]; c >= 'A' && c <= 'Z' {
+			buf[i] = c + ('a' - 'A')
+		} else {
+			buf[i] = c
+		}
+	}
+
+	if i := internal.IndexInvalid(buf[:]); i >= 0 {
+		return zero, &InvalidEncodingError{Byte: src[i], Index: i}
+	}
+
+	return internal.Decode(buf[:]), nil
+}
+
+// IsCanonical checks whether the given string is the canonical base32-encoded representation of an ID,
+// i.e. whether re-encoding the ID it decodes to yields exactly the same string - e.g. for identity-sensitive
+// contexts like signature canonicalization, where almost-valid strings must be rejected.
+//
+// Decoding is tolerant and does not validate its input (see FromEncodedStringStrict), so strings with
+// characters outside of the alphabet (including upper-case ones) decode to some ID as well - but
+// are never canonical.
+func IsCanonical(s string) bool {
+	if len(s) != SizeEncoded {
+		return false
+	}
+
+	var (
+		id  = internal.Decode([]byte(s))
+		enc = internal.Encode(&id)
+	)
+
+	return string(enc[:]) == s
+}
+
+// FromAny attempts to convert the given value into an ID, figuring out its representation
+// the same way ID.Scan does - but with byte slices accepted in their encoded form as well.
+//
+// When given a byte slice:
+//	- with a length of SizeBinary (10), its contents will be copied into the ID.
+//	- with a length of SizeEncoded (16), its contents will be decoded into the ID.
+//	- with a length of 0, a zero ID gets returned.
+//	- with any other length, returns InvalidDataSizeError.
+//
+// When given a string:
+//	- with a length of SizeEncoded (16), its contents will be decoded into the ID.
+//	- with a length of 0, a zero ID gets returned.
+//	- with any other length, returns InvalidDataSizeError.
+//
+// When given an ID, it gets returned as is. When given nil, a zero ID gets returned.
+//
+// When given any other type, returns a InvalidTypeError.
+func FromAny(v interface{}) (id ID, err error) {
+	switch v := v.(type) {
+	case []byte:
+		switch DetectFormat(v) {
+		case FormatBinary:
+			copy(id[:], v)
+		case FormatEncoded:
+			id = internal.Decode(v)
+		default:
+			if len(v) != 0 {
+				err = &InvalidDataSizeError{Size: len(v)}
+			}
+		}
+
+	case string:
+		switch len(v) {
+		case SizeEncoded:
+			id = internal.Decode(*(*[]byte)(unsafe.Pointer(&v)))
+		case 0:
+		default:
+			err = &InvalidDataSizeError{Size: len(v)}
+		}
+
+	case ID:
+		id = v
+
+	case nil:
+
+	default:
+		err = &InvalidTypeError{Value: v}
+	}
+
+	return
+}
+
+// FromInvertedString decodes a base32-encoded string representation of an ID with an inverted
+// timestamp, as returned by ID.InvertedString, into the binary representation of the original ID
+// and returns it.
+//
+// The string must have a length of 16. Returns a InvalidDataSizeError if it does not.
+func FromInvertedString(src string) (id ID, err error) {
+	if id, err = FromEncodedString(src); err != nil {
+		return
+	}
+
+	for i := 0; i < 5; i++ {
+		id[i] = ^id[i]
+	}
+
+	return
+}
+
+// FromDecimal parses a base-10 representation of an ID, as returned by ID.Decimal or ID.DecimalPadded,
+// and returns it.
+//
+// Leading zeroes are permitted. Returns an InvalidDecimalError if the string is empty, contains anything
+// but ASCII digits or represents a value which does not fit in 80 bits.
+func FromDecimal(src string) (id ID, err error) {
+	if len(src) == 0 {
+		return zero, &InvalidDecimalError{Input: src}
+	}
+
+	var hi, lo, carry uint64
+
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		if c < '0' || c > '9' {
+			return zero, &InvalidDecimalError{Input: src}
+		}
+
+		// (hi, lo) = (hi, lo) * 10 + digit, where hi holds the top 16 bits.
+		carry, lo = bits.Mul64(lo, 10)
+		hi = hi*10 + carry
+		lo, carry = bits.Add64(lo, uint64(c-'0'), 0)
+		hi += carry
+
+		if hi > 0xFFFF {
+			return zero, &InvalidDecimalError{Input: src}
+		}
+	}
+
+	binary.BigEndian.PutUint16(id[:], uint16(hi))
+	binary.BigEndian.PutUint64(id[2:], lo)
+
+	return id, nil
+}
+
+// FromBase62 parses a base-62 representation of an ID, as returned by ID.Base62, and returns it.
+//
+// Leading zeroes are permitted (and do not change the result). Returns an InvalidBase62Error if the string
+// is empty, contains anything but characters of the base-62 alphabet or represents a value which does
+// not fit in 80 bits.
+func FromBase62(src string) (id ID, err error) {
+	if len(src) == 0 {
+		return zero, &InvalidBase62Error{Input: src}
+	}
+
+	var hi, lo, carry uint64
+
+	for i := 0; i < len(src); i++ {
+		var d byte
+
+		switch c := src[i]; {
+		case c >= '0' && c <= '9':
+			d = c - '0'
+		case c >= 'A' && c <= 'Z':
+			d = c - 'A' + 10
+		case c >= 'a' && c <= 'z':
+			d = c - 'a' + 36
+		default:
+			return zero, &InvalidBase62Error{Input: src}
+		}
+
+		// (hi, lo) = (hi, lo) * 62 + digit, where hi holds the top 16 bits.
+		carry, lo = bits.Mul64(lo, 62)
+		hi = hi*62 + carry
+		lo, carry = bits.Add64(lo, uint64(d), 0)
+		hi += carry
+
+		if hi > 0xFFFF {
+			return zero, &InvalidBase62Error{Input: src}
+		}
+	}
+
+	binary.BigEndian.PutUint16(id[:], uint16(hi))
+	binary.BigEndian.PutUint64(id[2:], lo)
+
+	return id, nil
+}
+
+// DecodingTable returns a copy of the lookup table used to decode the canonical base32-encoded
+// representation of IDs. Each character of the alphabet maps to its 5-bit value, all other bytes
+// map to 0xFF - e.g. for validating encoded IDs the same way this package would, without
+// decoding them.
+func DecodingTable() [256]byte {
+	return internal.DecodingTable()
+}
+
+// EncodeAll returns the canonical base32-encoded string representations of the given IDs,
+// in the same order.
+//
+// All strings share a single underlying buffer, which is only released once none of them
+// are referenced anymore.
+func EncodeAll(ids []ID) []string {
+	var (
+		buf = make([]byte, len(ids)*SizeEncoded)
+		out = make([]string, len(ids))
+	)
+
+	for i := range ids {
+		enc := internal.Encode((*[10]byte)(&ids[i]))
+		copy(buf[i*SizeEncoded:], enc[:])
+	}
+
+	// The buffer never gets written to after this point, so this is safe.
+	all := *(*string)(unsafe.Pointer(&buf))
+	for i := range out {
+		out[i] = all[i*SizeEncoded : (i+1)*SizeEncoded]
+	}
+
+	return out
+}
+
+// DecodeAll decodes the given canonically base32-encoded string representations of IDs
+// into their binary representations, in the same order.
+//
+// Each string must have a length of 16. Returns a BatchDecodeError holding the index of the first
+// string which does not (and a nil slice) otherwise.
+func DecodeAll(src []string) ([]ID, error) {
+	ids := make([]ID, len(src))
+
+	for i := range src {
+		id, err := FromEncodedString(src[i])
+		if err != nil {
+			return nil, &BatchDecodeError{Index: i, Err: err}
+		}
+
+		ids[i] = id
+	}
+
+	return ids, nil
+}
+
+// ExtractAll scans the given text for canonically encoded IDs and returns them decoded, in the order
+// of their occurrence, e.g. for extracting IDs from unstructured log lines. Returns nil if none are found.
+//
+// A match is a word (a maximal run of ASCII letters, digits and underscores - so that substrings of
+// longer words never match) of exactly 16 characters, all within the alphabet of the encoding.
+// Every such word decodes into some ID, so coincidental words which happen to fit the criteria
+// (e.g. "abcdefghijklmnop") are false positives - callers which care should validate the results,
+// e.g. by checking whether their timestamps fall within a plausible range.
+func ExtractAll(text string) (ids []ID) {
+	dec := internal.DecodingTable()
+
+	for i := 0; i < len(text); {
+		if !isWordByte(text[i]) {
+			i++
+			continue
+		}
+
+		// Find the end of the word and whether all of it is within our alphabet.
+		j, valid := i, true
+		for ; j < len(text) && isWordByte(text[j]); j++ {
+			if dec[text[j]] == 0xFF {
+				valid = false
+			}
+		}
+
+		if valid && j-i == SizeEncoded {
+			ids = append(ids, internal.Decode([]byte(text[i:j])))
+		}
+
+		i = j
+	}
+
+	return ids
+}
+
+func isWordByte(b byte) bool {
+	return b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b == '_'
+}
+
+// BenchmarkCodec times the encoding and decoding of n sample IDs and returns the average time
+// per operation in nanoseconds - e.g. for a debug endpoint diagnosing the performance of a deployment,
+// alongside VectorizedCodec. It panics with an InvalidSizeError if n <= 0.
+//
+// It is a rough self-measurement, not a substitute for proper benchmarks: the result is subject
+// to timer resolution, CPU frequency scaling and whatever else runs concurrently - use a sufficiently
+// large n (e.g. 1e5) to get meaningful numbers.
+func BenchmarkCodec(n int) (encodeNsPerOp, decodeNsPerOp float64) {
+	if n <= 0 {
+		panic(&InvalidSizeError{Func: "BenchmarkCodec", Size: n})
+	}
+
+	var (
+		ids  = make([]ID, n)
+		encs = make([][SizeEncoded]byte, n)
+		base = AtTime(time.Now())
+	)
+
+	for i := range ids {
+		ids[i] = base
+		binary.BigEndian.PutUint32(ids[i][6:], uint32(i)*2654435761) // Varied payloads.
+	}
+
+	start := time.Now()
+	for i := range ids {
+		encs[i] = internal.Encode((*[10]byte)(&ids[i]))
+	}
+	encodeNsPerOp = float64(time.Since(start).Nanoseconds()) / float64(n)
+
+	start = time.Now()
+	for i := range encs {
+		ids[i] = internal.Decode(encs[i][:])
+	}
+	decodeNsPerOp = float64(time.Since(start).Nanoseconds()) / float64(n)
+
+	return
+}
+
+// VectorizedCodec reports whether the vectorized (SIMD) implementations of encoding and decoding
+// are in use, as opposed to the pure Go fallbacks.
+func VectorizedCodec() bool {
+	return internal.HasVectorSupport()
+}
+
+// IndexEntry returns a new byte slice holding the given prefix followed by the binary representation
+// of the ID, e.g. for composite secondary index keys of the form (externalKey, ID) in KV stores,
+// where the ID makes the keys unique and orders the entries sharing a prefix by time.
+//
+// Entries sharing a prefix sort like their IDs. The prefix gets copied, so it can be reused
+// by the caller afterwards.
+func IndexEntry(prefix []byte, id ID) []byte {
+	entry := make([]byte, len(prefix)+SizeBinary)
+	copy(entry, prefix)
+	copy(entry[len(prefix):], id[:])
+
+	return entry
+}
+
+// SplitIndexEntry decomposes an entry built by IndexEntry into its prefix of the given length
+// and its ID.
+//
+// The returned prefix is a subslice of the entry. Returns a InvalidDataSizeError if the entry does
+// not have a length of exactly prefixLen+10 (or prefixLen is negative).
+func SplitIndexEntry(entry []byte, prefixLen int) (prefix []byte, id ID, err error) {
+	if prefixLen < 0 || len(entry) != prefixLen+SizeBinary {
+		return nil, zero, &InvalidDataSizeError{Size: len(entry)}
+	}
+
+	copy(id[:], entry[prefixLen:])
+
+	return entry[:prefixLen:prefixLen], id, nil
+}
+
 type collection []ID
 
 func (ids collection) Len() int           { return len(ids) }
@@ -80,6 +645,269 @@ func Sort(s []ID) {
 	sort.Sort(collection(s))
 }
 
+// sorted returns the given IDs if they are sorted and a sorted copy of them otherwise.
+func sorted(ids []ID) []ID {
+	if sort.IsSorted(collection(ids)) {
+		return ids
+	}
+
+	s := make([]ID, len(ids))
+	copy(s, ids)
+	Sort(s)
+
+	return s
+}
+
+// Diff reconciles two sets of IDs, returning the IDs present only in a, only in b and in both - e.g.
+// to reconcile two datasets. It performs a single merge-style pass over both, so - unlike a set
+// difference built on maps - it needs no memory beyond the results.
+//
+// The IDs are expected to be sorted. If either slice is not, a sorted copy of it is used instead
+// (the given slices do not get mutated). All results are sorted.
+//
+// IDs present more than once are matched pairwise: an ID present twice in a and once in b ends up
+// once in both and once in onlyA.
+func Diff(a, b []ID) (onlyA, onlyB, both []ID) {
+	a, b = sorted(a), sorted(b)
+
+	var i, j int
+	for i < len(a) && j < len(b) {
+		switch c := a[i].Compare(b[j]); {
+		case c < 0:
+			onlyA = append(onlyA, a[i])
+			i++
+		case c > 0:
+			onlyB = append(onlyB, b[j])
+			j++
+		default:
+			both = append(both, a[i])
+			i++
+			j++
+		}
+	}
+
+	onlyA = append(onlyA, a[i:]...)
+	onlyB = append(onlyB, b[j:]...)
+
+	return
+}
+
+// MinUniquePrefixLen returns the smallest number of leading characters of the encoded representations
+// of the given IDs which suffices to tell all of them apart - akin to abbreviated commit hashes.
+//
+// IDs which are present more than once in the slice can't be told apart by definition, in which case
+// the full length (SizeEncoded) gets returned. An empty slice or a slice with a single ID results in 1.
+func MinUniquePrefixLen(ids []ID) int {
+	encs := make([]string, len(ids))
+	for i := range ids {
+		encs[i] = ids[i].String()
+	}
+
+	sort.Strings(encs)
+
+	// Once sorted, the longest common prefix of any two strings in the set is the longest common prefix
+	// of a pair of neighbours.
+	var n int
+	for i := 1; i < len(encs); i++ {
+		if l := commonPrefixLen(encs[i-1], encs[i]); l > n {
+			n = l
+		}
+	}
+
+	if n == SizeEncoded {
+		return SizeEncoded
+	}
+
+	return n + 1
+}
+
+// CompareLogical returns an integer comparing the given IDs by their time, then partition, then
+// sequence - ignoring their tick-tock bits and metabytes, unlike ID.Compare.
+//
+// Returns:
+// 	 0 - if a and b are logically equal (which they may be even if a != b),
+// 	-1 - if a is logically smaller than b,
+// 	 1 - if a is logically greater than b.
+//
+// ID.Compare orders IDs byte-wise, that is by time, tick-tock bit, metabyte, partition and sequence.
+// Within a timeframe revisited after a wall clock regression, IDs generated after the regression
+// then sort after all IDs generated before it - and IDs with different metabytes sort apart regardless
+// of their sequences. CompareLogical orders them by their sequences within the timeframe instead.
+func CompareLogical(a, b ID) int {
+	// The timestamps, sans the tick-tock bit.
+	ta, tb := binary.BigEndian.Uint64(a[:])>>25, binary.BigEndian.Uint64(b[:])>>25
+	if ta != tb {
+		if ta < tb {
+			return -1
+		}
+
+		return 1
+	}
+
+	// Partition and sequence.
+	pa, pb := binary.BigEndian.Uint32(a[6:]), binary.BigEndian.Uint32(b[6:])
+	switch {
+	case pa < pb:
+		return -1
+	case pa > pb:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Distance returns an estimate of the number of IDs generated between the given IDs (exclusive)
+// by the Generator of their Partition, regardless of the order they're given in.
+//
+// The estimate assumes the entire sequence pool (MaxSequence+1 IDs) got used in each timeframe
+// between them, as neither the sequence bounds of the Generator nor its actual usage are known.
+// Real usage tends to be sparse, so this is an upper bound rather than an accurate count. The tick-tock
+// bit is not taken into account.
+//
+// Returns false if the IDs do not share the same Partition, in which case no estimate can be made.
+func Distance(a, b ID) (int64, bool) {
+	if a.Partition() != b.Partition() {
+		return 0, false
+	}
+
+	var (
+		posA = int64(binary.BigEndian.Uint64(a[:])>>25)*(MaxSequence+1) + int64(a.Sequence())
+		posB = int64(binary.BigEndian.Uint64(b[:])>>25)*(MaxSequence+1) + int64(b.Sequence())
+	)
+
+	if posA > posB {
+		posA, posB = posB, posA
+	}
+
+	if posA == posB {
+		return 0, true
+	}
+
+	return posB - posA - 1, true
+}
+
+// InterArrivalHistogram counts the gaps between the timestamps of consecutive IDs (inter-arrival times)
+// into buckets with the given upper boundaries (inclusive, in ascending order), e.g. to characterize
+// the burstiness of a stream of IDs.
+//
+// The returned slice has one more element than the given boundaries - its last element counts
+// the gaps exceeding the last boundary. Gaps are only as precise as timestamps (4msec), so IDs
+// generated within the same timeframe are 0 apart.
+//
+// The IDs are expected to be sorted. If they are not, a sorted copy is used instead (the given slice
+// does not get mutated).
+func InterArrivalHistogram(ids []ID, buckets []time.Duration) []int {
+	counts := make([]int, len(buckets)+1)
+	ids = sorted(ids)
+
+	for i := 1; i < len(ids); i++ {
+		gap := time.Duration(ids[i].Timestamp() - ids[i-1].Timestamp())
+		counts[sort.Search(len(buckets), func(j int) bool {
+			return gap <= buckets[j]
+		})]++
+	}
+
+	return counts
+}
+
+// AuditReport describes the properties of a set of IDs, as determined by Audit.
+type AuditReport struct {
+	Count      int           // Number of IDs audited.
+	Sorted     bool          // Whether the IDs are sorted in ascending order.
+	Duplicates int           // Number of IDs which are duplicates of an ID earlier in the slice.
+	Partitions int           // Number of distinct partitions.
+	Span       time.Duration // Time between the earliest and the latest timestamp.
+}
+
+// String implements fmt.Stringer by returning a human-readable summary of the report, e.g.:
+//	count: 1024, sorted: true, duplicates: 0, partitions: 2, span: 1.2s
+func (r AuditReport) String() string {
+	return fmt.Sprintf("count: %d, sorted: %t, duplicates: %d, partitions: %d, span: %s",
+		r.Count, r.Sorted, r.Duplicates, r.Partitions, r.Span)
+}
+
+// Audit checks the given IDs for sortedness and uniqueness, and determines the number of distinct
+// partitions and their time span - all in a single pass, e.g. for validating exported dumps of IDs.
+//
+// An empty slice is reported as sorted, with a zero span.
+func Audit(ids []ID) AuditReport {
+	r := AuditReport{
+		Count:  len(ids),
+		Sorted: true,
+	}
+
+	if len(ids) == 0 {
+		return r
+	}
+
+	var (
+		seen       = make(map[ID]struct{}, len(ids))
+		partitions = make(map[Partition]struct{})
+		lo, hi     = ids[0].Timestamp(), ids[0].Timestamp()
+	)
+
+	for i := range ids {
+		if i > 0 && r.Sorted && ids[i-1].Compare(ids[i]) > 0 {
+			r.Sorted = false
+		}
+
+		if _, ok := seen[ids[i]]; ok {
+			r.Duplicates++
+		} else {
+			seen[ids[i]] = struct{}{}
+		}
+
+		partitions[ids[i].Partition()] = struct{}{}
+
+		if ts := ids[i].Timestamp(); ts < lo {
+			lo = ts
+		} else if ts > hi {
+			hi = ts
+		}
+	}
+
+	r.Partitions = len(partitions)
+	r.Span = time.Duration(hi - lo)
+
+	return r
+}
+
+// InferPartition returns the Partition shared by all IDs in the given slice.
+//
+// Returns false if the slice is empty or if the IDs do not all share the same Partition,
+// e.g. when they could not have been generated by a single Generator.
+func InferPartition(ids []ID) (Partition, bool) {
+	if len(ids) == 0 {
+		return Partition{}, false
+	}
+
+	p := ids[0].Partition()
+	for i := 1; i < len(ids); i++ {
+		if ids[i].Partition() != p {
+			return Partition{}, false
+		}
+	}
+
+	return p, true
+}
+
+// MinID returns the smallest possible ID - 10 zero bytes, equal to Zero() - as a sentinel for
+// the lower bound of range scans, e.g.:
+//	store.Range(sno.MinID(), sno.MaxID())
+func MinID() ID {
+	return zero
+}
+
+// MaxID returns the greatest possible ID - 10 bytes of 0xFF - as a sentinel for the upper bound
+// of range scans.
+//
+// Its timestamp is MaxTimestamp with the tick-tock bit set, which is beyond the timestamps of any ID
+// a Generator could generate (for all intents and purposes). It is only meant as a scan sentinel
+// and not as an actual ID.
+func MaxID() ID {
+	return ID{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}
+}
+
 // Zero returns the zero value of an ID, which is 10 zero bytes and equivalent to:
 //
 //	id := sno.ID{}
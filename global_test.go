@@ -1,8 +1,15 @@
 package sno
 
 import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestGlobal_Init(t *testing.T) {
@@ -42,6 +49,396 @@ func TestGlobal_Init(t *testing.T) {
 	})
 }
 
+func TestGlobal_AtTime(t *testing.T) {
+	tn := time.Now()
+	id := AtTime(tn)
+
+	if actual, expected := id.Timestamp(), tn.UnixNano()/TimeUnit*TimeUnit; actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	if actual, expected := id.Time().UnixNano(), tn.UnixNano()/TimeUnit*TimeUnit; actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	if id[4]&1 != 0 || id.Meta() != 0 || id.Partition() != (Partition{}) || id.Sequence() != 0 {
+		t.Errorf("expected zero payload, got [%v]", id[:])
+	}
+
+	if generated := NewWithTime(0, tn); generated.Compare(id) < 0 {
+		t.Errorf("expected [%s] to not sort before [%s]", generated, id)
+	}
+}
+
+func TestGlobal_AtTime_OutOfRange(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		t    time.Time
+		id   ID
+	}{
+		{"before-epoch", time.Unix(Epoch, 0).Add(-time.Nanosecond), ID{}},
+		{"year-1", time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC), ID{}},
+		{"after-max", TimestampExhaustionDate().Add(TimeUnit), AtTime(TimestampExhaustionDate())},
+		{"year-9999", time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC), AtTime(TimestampExhaustionDate())},
+	} {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			// Clamped.
+			if actual, expected := AtTime(c.t), c.id; actual != expected {
+				t.Errorf("expected [%v], got [%v]", expected, actual)
+			}
+
+			// Rejected.
+			id, err := AtTimeChecked(c.t)
+			if _, ok := err.(*TimestampRangeError); !ok {
+				t.Fatalf("expected error type [%T], got [%T]", &TimestampRangeError{}, err)
+			}
+
+			if !id.IsZero() {
+				t.Errorf("expected zero ID, got [%s]", id)
+			}
+		})
+	}
+
+	// The bounds themselves are embeddable.
+	for _, tn := range []time.Time{time.Unix(Epoch, 0), TimestampExhaustionDate()} {
+		id, err := AtTimeChecked(tn)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if actual, expected := id, AtTime(tn); actual != expected {
+			t.Errorf("expected [%v], got [%v]", expected, actual)
+		}
+	}
+}
+
+func TestGlobal_DefaultPartition(t *testing.T) {
+	prev := defaultGenerator()
+	defer func() {
+		generator.Store(prev)
+		DefaultPartition = ""
+	}()
+
+	// Simulates -ldflags "-X github.com/muyo/sno.DefaultPartition=1234".
+	DefaultPartition = "1234"
+	doInit()
+
+	if actual, expected := New(255).Partition().AsUint16(), uint16(1234); actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	for _, v := range []string{"65536", "-1", "0x10", "abc"} {
+		func() {
+			defer func() {
+				err := recover()
+				if actual, expected := err, (&InvalidDefaultPartitionError{Value: v}); !reflect.DeepEqual(actual, expected) {
+					t.Errorf("expected panic with [%v], got [%v]", expected, actual)
+				}
+			}()
+
+			DefaultPartition = v
+			doInit()
+		}()
+	}
+}
+
+func TestGlobal_Configure(t *testing.T) {
+	prev := defaultGenerator()
+	defer generator.Store(prev)
+
+	err := Configure(&GeneratorSnapshot{
+		SequenceMin: 1024,
+		SequenceMax: 1025,
+	})
+	if _, ok := err.(*InvalidSequenceBoundsError); !ok {
+		t.Errorf("expected error type [%T], got [%T]", &InvalidSequenceBoundsError{}, err)
+	}
+
+	if defaultGenerator() != prev {
+		t.Error("expected the generator to remain unchanged")
+	}
+
+	p := Partition{'C', 'F'}
+	if err := Configure(&GeneratorSnapshot{Partition: p}); err != nil {
+		t.Fatal(err)
+	}
+
+	if actual, expected := New(255).Partition(), p; actual != expected {
+		t.Errorf("expected [%v], got [%v]", expected, actual)
+	}
+
+	if actual, expected := NewWithTime(255, time.Now()).Partition(), p; actual != expected {
+		t.Errorf("expected [%v], got [%v]", expected, actual)
+	}
+}
+
+func TestGlobal_Configure_Concurrent(t *testing.T) {
+	prev := defaultGenerator()
+	defer generator.Store(prev)
+
+	var (
+		wg   sync.WaitGroup
+		p    = Partition{'C', 'C'}
+		stop = make(chan struct{})
+	)
+
+	// Meant to be run with -race - generation must observe either the previous or the new generator.
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					if actual := New(255).Partition(); actual != p && actual != prev.Partition() {
+						t.Errorf("expected [%v] or [%v], got [%v]", p, prev.Partition(), actual)
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < 8; i++ {
+		if err := Configure(&GeneratorSnapshot{Partition: p}); err != nil {
+			t.Error(err)
+		}
+	}
+
+	close(stop)
+	wg.Wait()
+}
+
+func TestGlobal_CompareLogical(t *testing.T) {
+	var (
+		tn   = time.Now()
+		base = AtTime(tn)
+		with = func(fn func(id *ID)) ID {
+			id := base
+			fn(&id)
+			return id
+		}
+	)
+
+	base[6], base[8] = 1, 1 // Leaves headroom for the increments below.
+
+	var (
+		tick    = with(func(id *ID) { id[4] |= 1 })
+		meta    = with(func(id *ID) { id[5] = 255 })
+		nextSeq = with(func(id *ID) { id[9]++ })
+		nextPar = with(func(id *ID) { id[7]++ })
+		later   = AtTime(tn.Add(time.Second))
+	)
+
+	for _, c := range []struct {
+		name     string
+		a, b     ID
+		logical  int
+		bytewise int
+	}{
+		{"identical", base, base, 0, 0},
+		{"tick-differs", base, tick, 0, -1},
+		{"meta-differs", base, meta, 0, -1},
+		{"sequence", base, nextSeq, -1, -1},
+		{"partition", nextSeq, nextPar, -1, -1},
+		{"time", later, tick, 1, 1},
+		// Generated after a regression (tick set), but with a lower sequence.
+		{"tick-vs-sequence", tick, nextSeq, -1, 1},
+		{"meta-vs-sequence", meta, nextSeq, -1, 1},
+	} {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			if actual, expected := CompareLogical(c.a, c.b), c.logical; actual != expected {
+				t.Errorf("expected [%d], got [%d]", expected, actual)
+			}
+
+			if actual, expected := CompareLogical(c.b, c.a), -c.logical; actual != expected {
+				t.Errorf("expected [%d], got [%d]", expected, actual)
+			}
+
+			if actual, expected := c.a.Compare(c.b), c.bytewise; actual != expected {
+				t.Errorf("expected [%d], got [%d]", expected, actual)
+			}
+		})
+	}
+}
+
+func TestGlobal_ComposeUnixNano(t *testing.T) {
+	var (
+		tn      = time.Now()
+		p       = Partition{'A', 'B'}
+		id, err = ComposeUnixNano(tn.UnixNano(), p, 255, 1024)
+	)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if actual, expected := id.Time().UnixNano(), tn.UnixNano()/TimeUnit*TimeUnit; actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	if actual, expected := id.Partition(), p; actual != expected {
+		t.Errorf("expected [%v], got [%v]", expected, actual)
+	}
+
+	if actual, expected := id.Meta(), byte(255); actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	if actual, expected := id.Sequence(), uint16(1024); actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	if id[4]&1 != 0 {
+		t.Errorf("expected no tick-tock bit, got [%v]", id[:])
+	}
+}
+
+func TestGlobal_ComposeUnixNano_OutOfRange(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		unixNano int64
+	}{
+		{"before-epoch", epochNsec - 1},
+		{"unix-epoch", 0},
+		{"min-int64", math.MinInt64},
+		{"after-max", epochNsec + (MaxTimestamp+1)*TimeUnit},
+	} {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			id, err := ComposeUnixNano(c.unixNano, Partition{}, 0, 0)
+			if _, ok := err.(*TimestampRangeError); !ok {
+				t.Fatalf("expected error type [%T], got [%T]", &TimestampRangeError{}, err)
+			}
+
+			if !id.IsZero() {
+				t.Errorf("expected zero ID, got [%s]", id)
+			}
+		})
+	}
+
+	// Bounds are inclusive.
+	if _, err := ComposeUnixNano(epochNsec, Partition{}, 0, 0); err != nil {
+		t.Errorf("expected no error, got [%v]", err)
+	}
+
+	if _, err := ComposeUnixNano(epochNsec+MaxTimestamp*TimeUnit, Partition{}, 0, 0); err != nil {
+		t.Errorf("expected no error, got [%v]", err)
+	}
+}
+
+func TestGlobal_NewID(t *testing.T) {
+	var (
+		tn = time.Date(2020, 3, 14, 15, 9, 26, 535897932, time.UTC)
+		p  = Partition{'N', 'I'}
+	)
+
+	for _, c := range []struct {
+		name string
+		fn   func() (ID, error)
+	}{
+		{"NewID", func() (ID, error) { return NewID(tn, 255, p, 65535), nil }},
+		{"NewIDChecked", func() (ID, error) { return NewIDChecked(tn, 255, p, 65535) }},
+	} {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			id, err := c.fn()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if actual, expected := id.Time(), tn.Truncate(TimeUnit); !actual.Equal(expected) {
+				t.Errorf("expected [%s], got [%s]", expected, actual)
+			}
+
+			if actual, expected := id.Meta(), byte(255); actual != expected {
+				t.Errorf("expected [%d], got [%d]", expected, actual)
+			}
+
+			if actual, expected := id.Partition(), p; actual != expected {
+				t.Errorf("expected [%v], got [%v]", expected, actual)
+			}
+
+			if actual, expected := id.Sequence(), uint16(65535); actual != expected {
+				t.Errorf("expected [%d], got [%d]", expected, actual)
+			}
+
+			if id[4]&1 != 0 {
+				t.Errorf("expected no tick-tock bit, got [%v]", id[:])
+			}
+
+			// Same layout as composed from Unix time.
+			if composed, _ := ComposeUnixNano(tn.UnixNano(), p, 255, 65535); composed != id {
+				t.Errorf("expected [%v], got [%v]", composed, id)
+			}
+		})
+	}
+}
+
+func TestGlobal_NewID_OutOfRange(t *testing.T) {
+	for _, c := range []struct {
+		name  string
+		t     time.Time
+		units int64
+	}{
+		{"before-epoch", time.Unix(Epoch, 0).Add(-time.Nanosecond), 0},
+		{"year-1", time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC), 0},
+		{"after-max", TimestampExhaustionDate().Add(TimeUnit), MaxTimestamp},
+		{"year-9999", time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC), MaxTimestamp},
+	} {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			// Clamped.
+			if actual, expected := NewID(c.t, 1, Partition{}, 1).Timestamp(), c.units*TimeUnit+epochNsec; actual != expected {
+				t.Errorf("expected [%d], got [%d]", expected, actual)
+			}
+
+			// Rejected.
+			id, err := NewIDChecked(c.t, 1, Partition{}, 1)
+			if _, ok := err.(*TimestampRangeError); !ok {
+				t.Fatalf("expected error type [%T], got [%T]", &TimestampRangeError{}, err)
+			}
+
+			if !id.IsZero() {
+				t.Errorf("expected zero ID, got [%s]", id)
+			}
+		})
+	}
+}
+
+func TestGlobal_IsCanonical(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		in       string
+		expected bool
+	}{
+		{"valid", "brpk4q72xwf2m63l", true},
+		{"zero", "2222222222222222", true},
+		{"max", "xxxxxxxxxxxxxxxx", true},
+		{"generated", New(255).String(), true},
+		{"empty", "", false},
+		{"too-short", "brpk4q72xwf2m63", false},
+		{"too-long", "brpk4q72xwf2m63ll", false},
+		{"upper-case", "BRPK4Q72XWF2M63L", false},
+		{"mixed-case", "brpk4q72xwf2m63L", false},
+		{"out-of-alphabet-digit", "brpk4q72xwf2m631", false},
+		{"out-of-alphabet-letter", "brpk4q72xwf2m63z", false},
+		{"padding", "brpk4q72xwf2m63=", false},
+		{"multi-byte", "brpk4q72xwf2m6é", false},
+	} {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			if actual, expected := IsCanonical(c.in), c.expected; actual != expected {
+				t.Errorf("expected [%t], got [%t]", expected, actual)
+			}
+		})
+	}
+}
+
 func TestGlobal_FromEncodedString_Valid(t *testing.T) {
 	src := "brpk4q72xwf2m63l"
 	expected := ID{78, 111, 33, 96, 160, 255, 154, 10, 16, 51}
@@ -68,6 +465,373 @@ func TestGlobal_FromEncodedString_Invalid(t *testing.T) {
 	}
 }
 
+func TestGlobal_FromEncodedStringStrict(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		in   string
+		id   ID
+		err  error
+	}{
+		{"valid", "brpk4q72xwf2m63l", ID{78, 111, 33, 96, 160, 255, 154, 10, 16, 51}, nil},
+		{"punctuation", "!!!!!!!!!!!!!!!!", zero, &InvalidEncodingError{Byte: '!', Index: 0}},
+		{"upper-case", "brpk4q72XWF2m63l", zero, &InvalidEncodingError{Byte: 'X', Index: 8}},
+		{"out-of-alphabet", "brpk4q72xwf2m63z", zero, &InvalidEncodingError{Byte: 'z', Index: 15}},
+		{"digit", "brpk4q12xwf2m63l", zero, &InvalidEncodingError{Byte: '1', Index: 6}},
+		{"size", "brpk4q72xwf2m63", zero, &InvalidDataSizeError{Size: 15}},
+	} {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			id, err := FromEncodedStringStrict(c.in)
+			if !reflect.DeepEqual(err, c.err) {
+				t.Errorf("expected [%v], got [%v]", c.err, err)
+			}
+
+			if id != c.id {
+				t.Errorf("expected [%v], got [%v]", c.id, id)
+			}
+
+			bid, berr := FromEncodedBytesStrict([]byte(c.in))
+			if !reflect.DeepEqual(berr, c.err) {
+				t.Errorf("expected [%v], got [%v]", c.err, berr)
+			}
+
+			if bid != c.id {
+				t.Errorf("expected [%v], got [%v]", c.id, bid)
+			}
+		})
+	}
+}
+
+func TestGlobal_FromEncodedStringInsensitive(t *testing.T) {
+	expected := ID{78, 111, 33, 96, 160, 255, 154, 10, 16, 51}
+
+	for _, in := range []string{"brpk4q72xwf2m63l", "BRPK4Q72XWF2M63L", "bRpK4q72XwF2m63L"} {
+		actual, err := FromEncodedStringInsensitive(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if actual != expected {
+			t.Errorf("%s: expected [%v], got [%v]", in, expected, actual)
+		}
+	}
+
+	for _, c := range []struct {
+		in  string
+		err error
+	}{
+		{"BRPK4Q72XWF2M63Z", &InvalidEncodingError{Byte: 'Z', Index: 15}},
+		{"BRPK4Q72YWF2M63L", &InvalidEncodingError{Byte: 'Y', Index: 8}},
+		{"BRPK-Q72XWF2M63L", &InvalidEncodingError{Byte: '-', Index: 4}},
+		{"BRPK4Q72XWF2M63", &InvalidDataSizeError{Size: 15}},
+	} {
+		if _, err := FromEncodedStringInsensitive(c.in); !reflect.DeepEqual(err, c.err) {
+			t.Errorf("%s: expected [%v], got [%v]", c.in, c.err, err)
+		}
+	}
+}
+
+func TestGlobal_DecodingTable(t *testing.T) {
+	const alphabet = "23456789abcdefghijklmnopqrstuvwx"
+
+	lut := DecodingTable()
+
+	for c := 0; c < len(lut); c++ {
+		expected := byte(0xFF)
+		if i := strings.IndexByte(alphabet, byte(c)); i != -1 {
+			expected = byte(i)
+		}
+
+		if actual := lut[c]; actual != expected {
+			t.Errorf("%q: expected [%#x], got [%#x]", c, expected, actual)
+		}
+	}
+
+	// Mutating the copy must not affect the table.
+	lut['2'] = 0xFF
+
+	if actual, expected := DecodingTable()['2'], byte(0); actual != expected {
+		t.Errorf("expected [%#x], got [%#x]", expected, actual)
+	}
+}
+
+func TestGlobal_EncodeAll(t *testing.T) {
+	if actual := EncodeAll([]ID{}); len(actual) != 0 {
+		t.Errorf("expected empty slice, got [%v]", actual)
+	}
+
+	ids := []ID{New(255), New(0), {}}
+	actual := EncodeAll(ids)
+
+	if len(actual) != len(ids) {
+		t.Fatalf("expected length [%d], got [%d]", len(ids), len(actual))
+	}
+
+	for i := range ids {
+		if actual, expected := actual[i], ids[i].String(); actual != expected {
+			t.Errorf("%d: expected [%s], got [%s]", i, expected, actual)
+		}
+	}
+}
+
+func TestGlobal_FromDecimal_Invalid(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		in   string
+	}{
+		{"empty", ""},
+		{"sign", "-1"},
+		{"space", " 1"},
+		{"letter", "12a4"},
+		{"overflow", "1208925819614629174706176"},
+		{"overflow-long", "99999999999999999999999999999999999999"},
+	} {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			id, err := FromDecimal(c.in)
+			if actual, expected := err, (&InvalidDecimalError{Input: c.in}); !reflect.DeepEqual(actual, expected) {
+				t.Errorf("expected [%v], got [%v]", expected, actual)
+			}
+
+			if !id.IsZero() {
+				t.Errorf("expected zero ID, got [%s]", id)
+			}
+		})
+	}
+
+	// Leading zeroes beyond the padded width are fine.
+	if id, err := FromDecimal("0000000000000000000000000000001"); err != nil || id != (ID{9: 1}) {
+		t.Errorf("expected [%v], got [%v] (error: %v)", ID{9: 1}, id, err)
+	}
+}
+
+func TestGlobal_FromBase62_Invalid(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		in   string
+	}{
+		{"empty", ""},
+		{"sign", "-1"},
+		{"space", " 1"},
+		{"symbol", "1qny1P34_qCF87"},
+		{"overflow", "62iEp5bu9VZbsW"},
+		{"overflow-long", "zzzzzzzzzzzzzzzzzzzz"},
+	} {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			id, err := FromBase62(c.in)
+			if actual, expected := err, (&InvalidBase62Error{Input: c.in}); !reflect.DeepEqual(actual, expected) {
+				t.Errorf("expected [%v], got [%v]", expected, actual)
+			}
+
+			if !id.IsZero() {
+				t.Errorf("expected zero ID, got [%s]", id)
+			}
+		})
+	}
+}
+
+func TestGlobal_BenchmarkCodec(t *testing.T) {
+	enc, dec := BenchmarkCodec(1e4)
+
+	if enc <= 0 {
+		t.Errorf("expected a positive encoding time, got [%f]", enc)
+	}
+
+	if dec <= 0 {
+		t.Errorf("expected a positive decoding time, got [%f]", dec)
+	}
+
+	defer func() {
+		err, ok := recover().(*InvalidSizeError)
+		if !ok {
+			t.Fatalf("expected a panic with [%T]", &InvalidSizeError{})
+		}
+
+		if actual, expected := err.Error(), "sno: BenchmarkCodec requires a positive size, got 0"; actual != expected {
+			t.Errorf("expected [%s], got [%s]", expected, actual)
+		}
+	}()
+
+	BenchmarkCodec(0)
+}
+
+func TestGlobal_IndexEntry(t *testing.T) {
+	id := ID{78, 111, 33, 96, 160, 255, 154, 10, 16, 51}
+
+	for _, prefix := range [][]byte{nil, {}, {'k'}, []byte("user:1234:"), make([]byte, 300)} {
+		entry := IndexEntry(prefix, id)
+
+		if actual, expected := len(entry), len(prefix)+SizeBinary; actual != expected {
+			t.Errorf("expected [%d], got [%d]", expected, actual)
+		}
+
+		p, actual, err := SplitIndexEntry(entry, len(prefix))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if expected := id; actual != expected {
+			t.Errorf("expected [%v], got [%v]", expected, actual)
+		}
+
+		if !bytes.Equal(p, prefix) {
+			t.Errorf("expected [%v], got [%v]", prefix, p)
+		}
+	}
+
+	// Entries sharing a prefix sort like their IDs.
+	var (
+		tn     = time.Now()
+		prefix = []byte("tenant:")
+		a      = IndexEntry(prefix, NewWithTime(255, tn))
+		b      = IndexEntry(prefix, NewWithTime(0, tn.Add(time.Second)))
+	)
+
+	if bytes.Compare(a, b) >= 0 {
+		t.Errorf("expected [%v] to sort before [%v]", a, b)
+	}
+
+	// The prefix must not be aliased.
+	prefix[0] = 'x'
+	if a[0] != 't' {
+		t.Error("expected the entry to not alias the prefix")
+	}
+}
+
+func TestGlobal_SplitIndexEntry_InvalidSize(t *testing.T) {
+	for _, c := range []struct {
+		name      string
+		entry     []byte
+		prefixLen int
+	}{
+		{"too-short", make([]byte, 9), 0},
+		{"too-long", make([]byte, 14), 3},
+		{"prefix-too-long", make([]byte, 12), 3},
+		{"negative-prefix", make([]byte, 10), -1},
+	} {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			_, _, err := SplitIndexEntry(c.entry, c.prefixLen)
+			if actual, expected := err, (&InvalidDataSizeError{Size: len(c.entry)}); !reflect.DeepEqual(actual, expected) {
+				t.Errorf("expected [%v], got [%v]", expected, actual)
+			}
+		})
+	}
+}
+
+func TestGlobal_ExtractAll(t *testing.T) {
+	var (
+		id   = ID{78, 111, 33, 96, 160, 255, 154, 10, 16, 51}
+		line = "2020-03-23T12:55:40Z INFO [req=brpk4q72xwf2m63l] processed brpk4q72xwf2m63lx," +
+			" xbrpk4q72xwf2m63l BRPK4Q72XWF2M63L brpk4q72xwf2m63_ brpk4q72xwf2m631 user_brpk4q72xwf2m63l (brpk4q72xwf2m63l)"
+	)
+
+	if actual, expected := ExtractAll(line), []ID{id, id}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected [%v], got [%v]", expected, actual)
+	}
+
+	if actual := ExtractAll("nothing to see here, 2020-03-23T12:55:40Z"); actual != nil {
+		t.Errorf("expected [nil], got [%v]", actual)
+	}
+
+	// Coincidental words fitting the criteria are indistinguishable from IDs.
+	if actual, expected := len(ExtractAll("abcdefghijklmnop")), 1; actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+}
+
+func TestGlobal_DecodeAll(t *testing.T) {
+	actual, err := DecodeAll([]string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(actual) != 0 {
+		t.Errorf("expected empty slice, got [%v]", actual)
+	}
+
+	ids := []ID{New(255), New(0), {}}
+
+	actual, err = DecodeAll(EncodeAll(ids))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(actual, ids) {
+		t.Errorf("expected [%v], got [%v]", ids, actual)
+	}
+
+	_, err = DecodeAll([]string{ids[0].String(), ids[1].String(), "brpk4q72xwf2m63", ids[2].String()})
+	if _, ok := err.(*BatchDecodeError); !ok {
+		t.Fatalf("expected error with type [%T], got [%T]", &BatchDecodeError{}, err)
+	}
+
+	berr := err.(*BatchDecodeError)
+	if actual, expected := berr.Index, 2; actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	if _, ok := berr.Unwrap().(*InvalidDataSizeError); !ok {
+		t.Errorf("expected error with type [%T], got [%T]", &InvalidDataSizeError{}, berr.Unwrap())
+	}
+
+	if actual, expected := err.Error(), "sno: failed to decode element at index 2: "+errInvalidDataSizeMsg; actual != expected {
+		t.Errorf("expected [%s], got [%s]", expected, actual)
+	}
+}
+
+func TestGlobal_FromInvertedString_Invalid(t *testing.T) {
+	_, err := FromInvertedString("brpk4q72xwf2m63")
+
+	if _, ok := err.(*InvalidDataSizeError); !ok {
+		t.Errorf("expected error with type [%T], got [%T]", &InvalidDataSizeError{}, err)
+	}
+}
+
+func TestGlobal_FromAny(t *testing.T) {
+	id := New(255)
+
+	for _, c := range []struct {
+		name   string
+		in     interface{}
+		out    ID
+		err    error
+		errMsg string
+	}{
+		{"nil", nil, ID{}, nil, ""},
+		{"id", id, id, nil, ""},
+		{"bytes-valid", id[:], id, nil, ""},
+		{"bytes-encoded", []byte(id.String()), id, nil, ""},
+		{"bytes-invalid", make([]byte, 3), zero, &InvalidDataSizeError{Size: 3}, errInvalidDataSizeMsg},
+		{"bytes-zero", []byte{}, zero, nil, ""},
+		{"string-valid", id.String(), id, nil, ""},
+		{"string-invalid", "123", zero, &InvalidDataSizeError{Size: 3}, errInvalidDataSizeMsg},
+		{"string-binary", string(id[:]), zero, &InvalidDataSizeError{Size: 10}, errInvalidDataSizeMsg},
+		{"string-zero", "", zero, nil, ""},
+		{"invalid", 69, ID{}, &InvalidTypeError{Value: 69}, fmt.Sprintf(errInvalidTypeFmt, 69)},
+	} {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			out, err := FromAny(c.in)
+
+			if actual, expected := out, c.out; actual != expected {
+				t.Errorf("expected [%s], got [%s]", expected, actual)
+			}
+
+			if err != nil && c.err == nil {
+				t.Errorf("got unexpected error: %s", err)
+			} else if actual, expected := reflect.TypeOf(err), reflect.TypeOf(c.err); actual != expected {
+				t.Errorf("expected error type [%s], got [%s]", expected, actual)
+			} else if err != nil && c.errMsg != "" && err.Error() != c.errMsg {
+				t.Errorf("expected error message [%s], got [%s]", c.errMsg, err.Error())
+			}
+		})
+	}
+}
+
 func TestGlobal_FromEncodedBytes_Valid(t *testing.T) {
 	src := []byte("brpk4q72xwf2m63l")
 	expected := ID{78, 111, 33, 96, 160, 255, 154, 10, 16, 51}
@@ -216,6 +980,25 @@ func makeCollectionSortTest(ids []ID) func(t *testing.T) {
 	}
 }
 
+func TestGlobal_MinID_MaxID(t *testing.T) {
+	if MinID() != Zero() {
+		t.Errorf("expected [%v], got [%v]", Zero(), MinID())
+	}
+
+	if MinID().Compare(MaxID()) >= 0 {
+		t.Error("expected MinID to sort before MaxID")
+	}
+
+	id := New(255)
+	if MinID().Compare(id) >= 0 || MaxID().Compare(id) <= 0 {
+		t.Errorf("expected [%s] to sort between the sentinels", id)
+	}
+
+	if actual, expected := MaxID().String(), "xxxxxxxxxxxxxxxx"; actual != expected {
+		t.Errorf("expected [%s], got [%s]", expected, actual)
+	}
+}
+
 func TestGlobal_Zero(t *testing.T) {
 	if actual := Zero(); actual != (ID{}) {
 		t.Error("Zero() not equal to ID{}")
@@ -227,3 +1010,313 @@ func TestGlobal_Zero_IsZero(t *testing.T) {
 		t.Error("Zero().IsZero() is not true")
 	}
 }
+
+func TestGlobal_Distance(t *testing.T) {
+	compose := func(units uint64, p Partition, seq uint16) (id ID) {
+		binary.BigEndian.PutUint64(id[:], units<<25)
+		id[6], id[7] = p[0], p[1]
+		binary.BigEndian.PutUint16(id[8:], seq)
+
+		return
+	}
+
+	var (
+		p = Partition{'D', 'D'}
+		a = compose(1000, p, 10)
+	)
+
+	for _, c := range []struct {
+		name string
+		b    ID
+		out  int64
+		ok   bool
+	}{
+		{"identical", a, 0, true},
+		{"adjacent", compose(1000, p, 11), 0, true},
+		{"same-timeframe", compose(1000, p, 20), 9, true},
+		{"next-timeframe", compose(1001, p, 10), MaxSequence, true},
+		{"next-timeframe-lower-sequence", compose(1001, p, 0), MaxSequence - 10, true},
+		{"earlier", compose(998, p, 10), 2*(MaxSequence+1) - 1, true},
+		{"other-partition", compose(1000, Partition{'D', 'E'}, 20), 0, false},
+	} {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			actual, ok := Distance(a, c.b)
+			if ok != c.ok {
+				t.Fatalf("expected [%t], got [%t]", c.ok, ok)
+			}
+
+			if expected := c.out; actual != expected {
+				t.Errorf("expected [%d], got [%d]", expected, actual)
+			}
+
+			if reversed, _ := Distance(c.b, a); reversed != actual {
+				t.Errorf("expected [%d] regardless of order, got [%d]", actual, reversed)
+			}
+		})
+	}
+}
+
+func TestGlobal_InterArrivalHistogram(t *testing.T) {
+	var (
+		tn      = time.Now()
+		offsets = []time.Duration{
+			0,
+			0,                       // 0
+			8 * time.Millisecond,    // 8ms
+			12 * time.Millisecond,   // 4ms
+			112 * time.Millisecond,  // 100ms
+			1112 * time.Millisecond, // 1s
+			1116 * time.Millisecond, // 4ms
+		}
+		ids     = make([]ID, len(offsets))
+		buckets = []time.Duration{0, 4 * time.Millisecond, 100 * time.Millisecond}
+	)
+
+	for i := range offsets {
+		ids[i] = NewWithTime(255, AtTime(tn).Time().Add(offsets[i]))
+	}
+
+	expected := []int{1, 2, 2, 1}
+
+	if actual := InterArrivalHistogram(ids, buckets); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected [%v], got [%v]", expected, actual)
+	}
+
+	// Unsorted input gets sorted - without mutating the input.
+	reversed := make([]ID, len(ids))
+	for i := range ids {
+		reversed[len(ids)-1-i] = ids[i]
+	}
+
+	first := reversed[0]
+
+	if actual := InterArrivalHistogram(reversed, buckets); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected [%v], got [%v]", expected, actual)
+	}
+
+	if reversed[0] != first {
+		t.Error("expected input to not be mutated")
+	}
+
+	if actual, expected := InterArrivalHistogram(nil, buckets), []int{0, 0, 0, 0}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected [%v], got [%v]", expected, actual)
+	}
+}
+
+func TestGlobal_Diff(t *testing.T) {
+	ids := make([]ID, 8)
+	for i := range ids {
+		ids[i] = ID{0, 0, 0, 0, 0, 0, 'D', 'F', 0, byte(i)}
+	}
+
+	for _, c := range []struct {
+		name               string
+		a, b               []ID
+		onlyA, onlyB, both []ID
+	}{
+		{"empty", nil, nil, nil, nil, nil},
+		{"disjoint", ids[:3], ids[3:6], ids[:3], ids[3:6], nil},
+		{"interleaved", []ID{ids[0], ids[2], ids[4]}, []ID{ids[1], ids[3]}, []ID{ids[0], ids[2], ids[4]}, []ID{ids[1], ids[3]}, nil},
+		{"overlapping", ids[:5], ids[3:], ids[:3], ids[5:], ids[3:5]},
+		{"identical", ids, ids, nil, nil, ids},
+		{"subset", ids[2:4], ids, nil, []ID{ids[0], ids[1], ids[4], ids[5], ids[6], ids[7]}, ids[2:4]},
+		{"unsorted", []ID{ids[4], ids[1], ids[3]}, []ID{ids[3], ids[0]}, []ID{ids[1], ids[4]}, ids[:1], ids[3:4]},
+		{"duplicates", []ID{ids[1], ids[1], ids[2]}, []ID{ids[1], ids[2], ids[2]}, ids[1:2], ids[2:3], ids[1:3]},
+	} {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			var (
+				a = append([]ID(nil), c.a...)
+				b = append([]ID(nil), c.b...)
+			)
+
+			onlyA, onlyB, both := Diff(a, b)
+
+			for _, r := range []struct {
+				name             string
+				actual, expected []ID
+			}{
+				{"onlyA", onlyA, c.onlyA},
+				{"onlyB", onlyB, c.onlyB},
+				{"both", both, c.both},
+			} {
+				if !reflect.DeepEqual(r.actual, r.expected) {
+					t.Errorf("%s: expected [%v], got [%v]", r.name, r.expected, r.actual)
+				}
+			}
+
+			// The inputs do not get mutated.
+			if !reflect.DeepEqual(a, c.a) || !reflect.DeepEqual(b, c.b) {
+				t.Errorf("expected the inputs to remain [%v] and [%v], got [%v] and [%v]", c.a, c.b, a, b)
+			}
+		})
+	}
+}
+
+func TestGlobal_Audit(t *testing.T) {
+	if actual, expected := Audit(nil), (AuditReport{Sorted: true}); actual != expected {
+		t.Errorf("expected [%s], got [%s]", expected, actual)
+	}
+
+	var (
+		tn = AtTime(time.Now()).Time()
+		a  = NewWithTime(255, tn)
+		b  = NewWithTime(255, tn.Add(time.Second))
+		c  = NewWithTime(255, tn.Add(1500*time.Millisecond))
+		d  = c
+	)
+
+	d[6], d[7] = d[6]+1, d[7]+1 // Different partition.
+
+	sorted := []ID{a, b, c, d}
+	Sort(sorted)
+
+	expected := AuditReport{
+		Count:      4,
+		Sorted:     true,
+		Duplicates: 0,
+		Partitions: 2,
+		Span:       1500 * time.Millisecond,
+	}
+
+	if actual := Audit(sorted); actual != expected {
+		t.Errorf("expected [%s], got [%s]", expected, actual)
+	}
+
+	expected = AuditReport{
+		Count:      6,
+		Sorted:     false,
+		Duplicates: 2,
+		Partitions: 2,
+		Span:       1500 * time.Millisecond,
+	}
+
+	if actual := Audit([]ID{c, a, b, d, a, c}); actual != expected {
+		t.Errorf("expected [%s], got [%s]", expected, actual)
+	}
+
+	if actual, expected := expected.String(), "count: 6, sorted: false, duplicates: 2, partitions: 2, span: 1.5s"; actual != expected {
+		t.Errorf("expected [%s], got [%s]", expected, actual)
+	}
+}
+
+func TestGlobal_InferPartition(t *testing.T) {
+	var (
+		a = ID{0, 0, 0, 0, 0, 0, 128, 255, 0, 1}
+		b = ID{0, 0, 0, 0, 1, 0, 128, 255, 0, 2}
+		c = ID{0, 0, 0, 0, 1, 0, 255, 128, 0, 3}
+	)
+
+	for _, c := range []struct {
+		name string
+		ids  []ID
+		p    Partition
+		ok   bool
+	}{
+		{"empty", []ID{}, Partition{}, false},
+		{"single", []ID{a}, Partition{128, 255}, true},
+		{"consistent", []ID{a, b, a}, Partition{128, 255}, true},
+		{"mixed", []ID{a, b, c}, Partition{}, false},
+	} {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			p, ok := InferPartition(c.ids)
+
+			if actual, expected := ok, c.ok; actual != expected {
+				t.Errorf("expected [%v], got [%v]", expected, actual)
+			}
+
+			if actual, expected := p, c.p; actual != expected {
+				t.Errorf("expected [%v], got [%v]", expected, actual)
+			}
+		})
+	}
+}
+
+func TestGlobal_MinUniquePrefixLen(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		in   []string
+		out  int
+	}{
+		{"empty", []string{}, 1},
+		{"single", []string{"brpk4q72xwf2m63l"}, 1},
+		{"disjoint", []string{"brpk4q72xwf2m63l", "crpk4q72xwf2m63l", "xrpk4q72xwf2m63l"}, 1},
+		{"partial", []string{"brpk4q72xwf2m63l", "b2222222xwf2m63l", "brpkxxxxxxxxxxxx"}, 5},
+		{"near-identical", []string{"brpk4q72xwf2m63l", "brpk4q72xwf2m63m", "brpk222222222222"}, 16},
+		{"duplicates", []string{"brpk4q72xwf2m63l", "b2222222xwf2m63l", "brpk4q72xwf2m63l"}, 16},
+	} {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			ids := make([]ID, len(c.in))
+			for i, s := range c.in {
+				ids[i] = mustDecode(t, s)
+			}
+
+			if actual, expected := MinUniquePrefixLen(ids), c.out; actual != expected {
+				t.Errorf("expected [%d], got [%d]", expected, actual)
+			}
+		})
+	}
+}
+
+func mustDecode(t *testing.T, s string) ID {
+	t.Helper()
+
+	id, err := FromEncodedString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return id
+}
+
+func TestGlobal_TimestampExhaustionDate(t *testing.T) {
+	date := TimestampExhaustionDate()
+
+	if actual, expected := date.Format(time.RFC3339Nano), "2079-09-07T15:47:35.548Z"; actual != expected {
+		t.Errorf("expected [%s], got [%s]", expected, actual)
+	}
+
+	// The date itself is embeddable and maps to MaxTimestamp.
+	id, err := ComposeUnixNano(date.UnixNano(), Partition{}, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if actual, expected := uint64(id.Timestamp()-epochNsec)/TimeUnit, uint64(MaxTimestamp); actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	if _, err := ComposeUnixNano(date.UnixNano()+TimeUnit, Partition{}, 0, 0); err == nil {
+		t.Errorf("expected an error past the exhaustion date")
+	}
+}
+
+func TestGlobal_MaxLifetime(t *testing.T) {
+	const perSecond = 16384000
+
+	until := time.Until(TimestampExhaustionDate())
+
+	for _, c := range []struct {
+		rate     int
+		expected time.Duration
+	}{
+		{0, until},
+		{1, until},
+		{perSecond, until},
+		{2 * perSecond, until / 2},
+		{8 * perSecond, until / 8},
+	} {
+		c := c
+		t.Run(fmt.Sprintf("%d", c.rate), func(t *testing.T) {
+			actual := MaxLifetime(c.rate)
+
+			// Both sides are relative to now, so allow for the time elapsed in between.
+			if d := c.expected - actual; d < -time.Second || d > time.Second {
+				t.Errorf("expected [%s], got [%s]", c.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package sno
+
+// NewGoldenGenerator returns a new Generator which generates a fully reproducible sequence of IDs,
+// independent of wall time, e.g. for tests which compare generated IDs against a committed golden file.
+// It is meant for tests only.
+//
+// Instead of the wall clock, the Generator follows a clock which starts in the time unit following
+// seed.WallHi and advances by exactly one TimeUnit whenever the sequence pool gets exhausted, i.e.
+// every Cap() calls to New (see GeneratorSnapshot.LogicalClock, which gets enforced). As such, the same seed
+// always results in the same IDs for the same sequence of calls - as long as the calls are made
+// sequentially.
+//
+// Options which would defeat reproducibility get ignored: the sequence never gets jittered. Panics
+// if the seed is otherwise not a valid snapshot (see NewGenerator).
+func NewGoldenGenerator(seed GeneratorSnapshot) *Generator {
+	seed.LogicalClock = true
+	seed.Jitter = 0
+
+	g, err := newGeneratorFromSnapshot(seed, GeneratorOptions{}, nil)
+	if err != nil {
+		panic(err)
+	}
+
+	start := uint64(seed.WallHi) + 1
+	g.clock = func() uint64 {
+		return start
+	}
+
+	return g
+}
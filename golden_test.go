@@ -0,0 +1,64 @@
+package sno
+
+import (
+	"bufio"
+	"os"
+	"testing"
+)
+
+func TestNewGoldenGenerator_Golden(t *testing.T) {
+	f, err := os.Open("testdata/golden_generator.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	g := NewGoldenGenerator(GeneratorSnapshot{
+		Partition:   Partition{'G', 'D'},
+		SequenceMin: 1024,
+		SequenceMax: 1031,
+		WallHi:      1 << 30,
+	})
+
+	var (
+		s = bufio.NewScanner(f)
+		n int
+	)
+
+	for ; s.Scan(); n++ {
+		if actual, expected := g.New(byte(n)).String(), s.Text(); actual != expected {
+			t.Errorf("%d: expected [%s], got [%s]", n, expected, actual)
+		}
+	}
+
+	if err := s.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if actual, expected := n, 4*g.Cap()+3; actual != expected {
+		t.Errorf("expected [%d] golden IDs, got [%d]", expected, actual)
+	}
+}
+
+func TestNewGoldenGenerator_Clock(t *testing.T) {
+	g := NewGoldenGenerator(GeneratorSnapshot{
+		Partition:   Partition{'G', 'C'},
+		SequenceMin: 16,
+		SequenceMax: 23,
+		Jitter:      2,
+	})
+
+	for i := 0; i < 3*g.Cap(); i++ {
+		id := g.New(255)
+
+		// The clock starts right after WallHi and advances by one unit every Cap() IDs.
+		if actual, expected := uint64(id.Timestamp()-epochNsec)/TimeUnit, uint64(1+i/g.Cap()); actual != expected {
+			t.Errorf("%d: expected [%d], got [%d]", i, expected, actual)
+		}
+
+		// Jitter gets ignored.
+		if actual, expected := id.Sequence(), uint16(16+i%g.Cap()); actual != expected {
+			t.Errorf("%d: expected [%d], got [%d]", i, expected, actual)
+		}
+	}
+}
@@ -2,8 +2,16 @@ package sno
 
 import (
 	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
 	"database/sql/driver"
+	"encoding/base32"
 	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
 	"time"
 	"unsafe"
 
@@ -26,7 +34,7 @@ const (
 	TimeUnit = 4e6
 
 	// MaxTimestamp is the max number of time units that can be embedded in an ID's timestamp.
-	// Corresponds to 2079-09-07 15:47:35.548 UTC in our custom epoch.
+	// Corresponds to 2079-09-07 15:47:35.548 UTC in our custom epoch. See TimestampExhaustionDate.
 	MaxTimestamp = 1<<39 - 1
 
 	// MaxPartition is the max Partition number when represented as a uint16.
@@ -36,6 +44,10 @@ const (
 	// MaxSequence is the max sequence number supported by generators. As bounds can be set
 	// individually - this is the upper cap and equals max uint16 (65535).
 	MaxSequence = 1<<16 - 1
+
+	// MaxTenant is the max tenant id that can be embedded in an ID via Generator.NewForTenant.
+	// It equals max uint24 (16777215).
+	MaxTenant = 1<<24 - 1
 )
 
 // ID is the binary representation of a sno ID.
@@ -53,6 +65,18 @@ const (
 //
 type ID [SizeBinary]byte
 
+// Key is the array form of an ID, for code which handles keys of various kinds uniformly as
+// arrays (e.g. generic sorted maps or trees constrained to comparable array types). See KeyOf.
+//
+// Note that ID itself is a comparable array type as well - it is directly usable as a map key
+// and with ==, and orders the same way as its Key when compared byte-wise (see ID.Compare).
+type Key = [SizeBinary]byte
+
+// KeyOf returns the given ID as a Key. The conversion is free - it merely drops the methods of the ID.
+func KeyOf(id ID) Key {
+	return Key(id)
+}
+
 // Time returns the timestamp of the ID as a time.Time struct.
 func (id ID) Time() time.Time {
 	var (
@@ -64,11 +88,81 @@ func (id ID) Time() time.Time {
 	return time.Unix(s, ns)
 }
 
+// TimeWithEpoch returns the timestamp of the ID as a time.Time struct like Time, but relative to
+// the given epoch (in seconds since the Unix epoch) instead of the package's Epoch - for IDs generated
+// by a Generator with a custom epoch (see GeneratorSnapshot.Epoch).
+func (id ID) TimeWithEpoch(epoch int64) time.Time {
+	var (
+		units = int64(binary.BigEndian.Uint64(id[:]) >> 25)
+		s     = units/250 + epoch
+		ns    = (units % 250) * TimeUnit
+	)
+
+	return time.Unix(s, ns)
+}
+
 // Timestamp returns the timestamp of the ID as nanoseconds relative to the Unix epoch.
 func (id ID) Timestamp() int64 {
 	return int64(binary.BigEndian.Uint64(id[:])>>25)*TimeUnit + epochNsec
 }
 
+// PrecisionLoss returns the bound of the error of the time of the ID (as returned by Time and Timestamp)
+// relative to the time it got generated with - that is TimeUnit (4msec), regardless of the ID.
+//
+// Timestamps get truncated to the TimeUnit when embedded, so the fraction below it is lost and not
+// recoverable - not even for IDs generated via NewWithTime with times of higher precision. For any ID
+// generated with a time t:
+//	0 <= t.Sub(id.Time()) < id.PrecisionLoss()
+func (id ID) PrecisionLoss() time.Duration {
+	return TimeUnit
+}
+
+// PossibleWallTimeRange estimates the range of wall clock times (both inclusive) the ID could have
+// been generated at, given a snapshot of the Generator which generated it, e.g. for reconstructing
+// the true times of events after clock incidents.
+//
+// IDs without the tick-tock bit set are assumed to have been generated while the wall clock was sound,
+// so the range simply spans their timeframe. IDs with the bit set got generated after a wall clock
+// regression, with the regressed time embedded - and since it is not known whether the clock was
+// wrong before or after the regression, the range extends up to the end of the highest timeframe
+// the Generator recorded before it regressed (the snapshot's WallSafe).
+//
+// It is an estimate - in particular, a single snapshot only carries the bookkeeping of the most recent
+// regression, so for IDs generated during earlier regressions the range may be too narrow.
+func (id ID) PossibleWallTimeRange(snap GeneratorSnapshot) (earliest, latest time.Time) {
+	var (
+		units = int64(binary.BigEndian.Uint64(id[:]) >> 25)
+		hi    = units
+	)
+
+	if id[4]&1 == 1 && snap.WallSafe > hi {
+		hi = snap.WallSafe
+	}
+
+	epoch := snap.Epoch
+	if epoch == 0 {
+		epoch = Epoch
+	}
+
+	return id.TimeWithEpoch(epoch), time.Unix(epoch, (hi+1)*TimeUnit-1)
+}
+
+// Age returns the time elapsed since the timestamp of the ID, i.e. time.Since(id.Time()).
+//
+// Timestamps are embedded with a 4msec resolution (see TimeUnit), so the age of an ID is only precise
+// to within 4msec. The age of an ID generated with a timestamp in the future is negative.
+func (id ID) Age() time.Duration {
+	return time.Since(id.Time())
+}
+
+// OlderThan checks whether the age of the ID exceeds d.
+//
+// Since timestamps are embedded with a 4msec resolution, an ID may be reported as older than d
+// up to 4msec before the time it got generated at actually became older than d.
+func (id ID) OlderThan(d time.Duration) bool {
+	return id.Age() > d
+}
+
 // Meta returns the metabyte of the ID.
 func (id ID) Meta() byte {
 	return id[5]
@@ -84,6 +178,240 @@ func (id ID) Sequence() uint16 {
 	return uint16(id[8])<<8 | uint16(id[9])
 }
 
+// WithMeta returns a copy of the ID with its metabyte replaced by the given one, and its timestamp,
+// partition and sequence untouched - e.g. to re-stamp the metabyte of an existing ID without
+// generating a new one. The receiver does not get modified.
+//
+// Checksummed IDs (see VerifyChecksum) lose their checksum, as the metabyte holds it.
+func (id ID) WithMeta(meta byte) ID {
+	id[5] = meta
+
+	return id
+}
+
+// WithPartition returns a copy of the ID with its partition replaced by the given one, and its timestamp,
+// metabyte and sequence untouched. The receiver does not get modified.
+//
+// Note that the resulting ID may collide with an ID generated for that partition (by a Generator
+// which owns it) and that checksummed IDs (see VerifyChecksum) fail to verify afterwards.
+func (id ID) WithPartition(p Partition) ID {
+	id[6] = p[0]
+	id[7] = p[1]
+
+	return id
+}
+
+// SequenceFraction returns the position of the ID's sequence within the given sequence pool as
+// a value in [0, 1], where 0 is min and 1 is max, e.g. for visualizing how full timeframes were
+// when IDs got generated.
+//
+// IDs do not carry the bounds of the pool of the Generator which generated them, so they must be
+// supplied (see GeneratorSnapshot.SequenceMin and SequenceMax). The bounds can be given in either
+// order. Sequences outside of the bounds get clamped and a pool with identical bounds yields 0.
+func (id ID) SequenceFraction(min, max uint16) float64 {
+	if min > max {
+		min, max = max, min
+	}
+
+	switch seq := id.Sequence(); {
+	case seq <= min:
+		return 0
+	case seq >= max:
+		return 1
+	default:
+		return float64(seq-min) / float64(max-min)
+	}
+}
+
+// PartitionLocalKey returns an 8 byte key composed of the timestamp (including the tick-tock bit) and
+// the sequence of the ID, e.g. for use as the row key within a partition-keyed table - a zero byte
+// followed by the 5 bytes of the timestamp and the 2 bytes of the sequence, so that the key can
+// be read as a big-endian uint64 as well.
+//
+// The metabyte and partition get dropped. The key therefore assumes the partition gets stored
+// separately (e.g. encoded in the table or column the key belongs to) and - as IDs sort by their
+// metabyte before the partition and sequence - the order of keys only matches that of the IDs
+// within a partition as long as they share the same metabyte as well (or the metabyte gets
+// stored separately too).
+func (id ID) PartitionLocalKey() (key [8]byte) {
+	copy(key[1:6], id[:5])
+	key[6] = id[8]
+	key[7] = id[9]
+
+	return
+}
+
+// SeriesKey returns a compact key composed of the timestamp and the partition of the ID, e.g. for use
+// as the row key of time-series databases keyed by (series, time) where the Partition identifies
+// the series.
+//
+// The 39 bits of the timestamp (in sno time units, without the tick-tock bit) occupy bits 16-54 and
+// the 16 bits of the partition bits 0-15 - the top 9 bits are always zero. Keys therefore sort by time
+// first, then by partition. Unlike the IDs themselves (which sort by their metabyte before
+// the partition), the metabyte and sequence are not part of the key - all IDs of a partition
+// within the same timeframe share the same key.
+func (id ID) SeriesKey() uint64 {
+	return binary.BigEndian.Uint64(id[:])>>25<<16 | uint64(id[6])<<8 | uint64(id[7])
+}
+
+// Tenant returns the 24-bit tenant id embedded across the metabyte and partition of the ID
+// by Generator.NewForTenant.
+//
+// For IDs generated otherwise, the value has no meaning.
+func (id ID) Tenant() uint32 {
+	return uint32(id[5])<<16 | uint32(id[6])<<8 | uint32(id[7])
+}
+
+// DefaultSchemaVersionBits is the default number of high bits of the metabyte which hold a schema version,
+// by the convention Generator.NewVersioned follows, e.g. for records whose format evolves over time: the high
+// bits hold the version of the schema of the record an ID identifies and the remaining low bits hold
+// a payload which gets interpreted according to that version.
+//
+// The default split is 3 bits, i.e. 8 versions with a 5-bit payload each. Generators may use another split
+// (see GeneratorSnapshot.SchemaVersionBits), in which case their IDs must be read via ID.SchemaSplit.
+const DefaultSchemaVersionBits = 3
+
+// SchemaVersion returns the schema version embedded in the high bits of the metabyte by
+// Generator.NewVersioned, with the default split (see DefaultSchemaVersionBits).
+//
+// For IDs generated otherwise, the value has no meaning.
+func (id ID) SchemaVersion() uint8 {
+	return id[5] >> (8 - DefaultSchemaVersionBits)
+}
+
+// SchemaPayload returns the payload embedded in the low bits of the metabyte by Generator.NewVersioned
+// alongside the schema version, with the default split (see DefaultSchemaVersionBits).
+//
+// For IDs generated otherwise, the value has no meaning.
+func (id ID) SchemaPayload() uint8 {
+	return id[5] & (0xFF >> DefaultSchemaVersionBits)
+}
+
+// SchemaSplit returns the schema version and the payload embedded in the metabyte by Generator.NewVersioned,
+// split with the given number of version bits (see GeneratorSnapshot.SchemaVersionBits) - 0 meaning
+// DefaultSchemaVersionBits.
+//
+// Panics with an InvalidSchemaVersionBitsError if bits exceeds 8. For IDs generated otherwise,
+// the values have no meaning.
+func (id ID) SchemaSplit(bits uint8) (version uint8, payload uint8) {
+	if bits == 0 {
+		bits = DefaultSchemaVersionBits
+	} else if bits > 8 {
+		panic(&InvalidSchemaVersionBitsError{Bits: bits})
+	}
+
+	// Shifts by 8 yield 0, so an 8-bit version leaves no room for the payload.
+	return uint8(uint16(id[5]) >> (8 - bits)), uint8(uint16(id[5]) & (0xFF >> bits))
+}
+
+// WithSchemaVersion returns a copy of the ID with its metabyte re-packed to the given schema version
+// and split (see SchemaSplit), keeping its payload, e.g. to migrate IDs generated via Generator.NewVersioned
+// to a new version of their schema - or to a new split, with bits being the split of the ID and toBits
+// the new one (0 meaning DefaultSchemaVersionBits for both).
+//
+// The timestamp, partition and sequence of the ID are left as they are, so the copy remains unique
+// among the IDs of the Generator which generated the original.
+//
+// Returns an InvalidSchemaVersionError if the version or the payload do not fit the new split and
+// an InvalidSchemaVersionBitsError if either split exceeds 8 bits.
+func (id ID) WithSchemaVersion(version uint8, bits, toBits uint8) (ID, error) {
+	if bits > 8 {
+		return zero, &InvalidSchemaVersionBitsError{Bits: bits}
+	}
+
+	if toBits == 0 {
+		toBits = DefaultSchemaVersionBits
+	} else if toBits > 8 {
+		return zero, &InvalidSchemaVersionBitsError{Bits: toBits}
+	}
+
+	_, payload := id.SchemaSplit(bits)
+
+	packed, ok := packSchema(version, payload, toBits)
+	if !ok {
+		return zero, &InvalidSchemaVersionError{Version: version, Payload: payload, Bits: toBits}
+	}
+
+	id[5] = packed
+
+	return id, nil
+}
+
+// packSchema packs the given schema version and payload into a metabyte with the given number
+// of version bits (in range [1, 8]), reporting whether they fit.
+func packSchema(version, payload, bits uint8) (meta byte, ok bool) {
+	if uint16(version) >= 1<<bits || uint16(payload) >= 1<<(8-bits) {
+		return 0, false
+	}
+
+	// A shift by 8 yields 0, so an 8-bit version leaves no room for the payload.
+	return byte(uint16(version)<<(8-bits) | uint16(payload)), true
+}
+
+// Seed24 returns a stable 24-bit value derived from the payload of the ID (its metabyte, partition
+// and sequence), e.g. for use as an RGB color or as the seed of an identicon-style avatar.
+//
+// The value is meant for display purposes only - it does not preserve the sort order of IDs and,
+// since the timestamp is not part of the derivation, IDs with identical payloads (e.g. the first IDs
+// generated in subsequent timeframes by the same Generator) share the same seed.
+func (id ID) Seed24() uint32 {
+	h := fnv32a(id[5:])
+
+	// XOR-fold the high byte into the lower 24 bits.
+	return (h>>24 ^ h) & 0xFFFFFF
+}
+
+// MaybeUserTimed heuristically checks whether the ID was likely generated via NewWithTime
+// (e.g. when porting old IDs) rather than via New, given the time the Generator which generated
+// it started operating at. Meant for cleaning up data after imports.
+//
+// IDs are not marked in any way when generated with user-specified timestamps, so all this does
+// is check whether the ID falls outside of the operational window of the Generator - e.g. it has
+// a timestamp before genStart or in the future. IDs with the tick-tock bit set are never
+// reported, as NewWithTime never sets it.
+//
+// The heuristic has false negatives - IDs generated via NewWithTime with times within the window
+// are indistinguishable from those generated via New - and false positives if genStart is later
+// than the actual start of generation, e.g. when IDs came from multiple Generators, or if the wall
+// clock of the Generator was ahead of the local one.
+func (id ID) MaybeUserTimed(genStart time.Time) bool {
+	if id[4]&1 == 1 {
+		return false
+	}
+
+	// Compare at the resolution of the timestamps.
+	ts := id.Timestamp()
+
+	return ts < genStart.UnixNano()/TimeUnit*TimeUnit || ts > time.Now().UnixNano()
+}
+
+// Shard returns a stable shard index in [0, n) derived from the partition and sequence of the ID,
+// e.g. for routing IDs to one of n workers downstream. It panics with an InvalidSizeError if n <= 0.
+//
+// Partitions and sequences tend to be sequential, so taking them modulo n would skew the distribution.
+// Instead, they get hashed (FNV-1a) and the hash gets mapped onto [0, n), which distributes IDs roughly
+// uniformly across shards as long as the sample spans a reasonable number of distinct sequences
+// or partitions. The timestamp and metabyte are not part of the derivation, so all IDs with the same
+// partition and sequence land on the same shard.
+func (id ID) Shard(n int) int {
+	if n <= 0 {
+		panic(&InvalidSizeError{Func: "Shard", Size: n})
+	}
+
+	// Maps the 32-bit hash onto [0, n) via a multiply-shift instead of a modulo,
+	// which avoids its bias towards lower shards.
+	return int(uint64(fnv32a(id[6:])) * uint64(n) >> 32)
+}
+
+// VerifyChecksum checks whether the metabyte of the ID matches the checksum (CRC-8) over its
+// other 9 bytes, as embedded by Generators with GeneratorSnapshot.Checksum set.
+//
+// IDs generated without a checksum are reported as corrupted, unless their metabyte happens
+// to match (roughly a 1 in 256 chance).
+func (id ID) VerifyChecksum() bool {
+	return id[5] == id.checksum()
+}
+
 // IsZero checks whether the ID is a zero value.
 func (id ID) IsZero() bool {
 	return id == zero
@@ -98,16 +426,183 @@ func (id ID) String() string {
 	return *(*string)(unsafe.Pointer(&dst))
 }
 
+// pseudonymEncoding encodes pseudonyms using the same alphabet as IDs, without padding.
+var pseudonymEncoding = base32.NewEncoding(internal.Alphabet()).WithPadding(base32.NoPadding)
+
+// Pseudonym returns a pseudonymized token for the ID: the base32-encoded HMAC-SHA256 of the ID keyed
+// with the given salt, e.g. for logging IDs to third-party systems - which can correlate events by it
+// without ever seeing the ID itself.
+//
+// The pseudonym is stable - the same ID always results in the same pseudonym under the same salt - but
+// non-reversible: the ID can not be recovered from it, nor can the pseudonyms of the same ID under
+// different salts be correlated. The salt must be kept secret, as IDs are guessable enough to be recovered
+// by brute force otherwise. The pseudonym does NOT sort the way the ID does.
+func (id ID) Pseudonym(salt []byte) string {
+	mac := hmac.New(sha256.New, salt)
+	_, _ = mac.Write(id[:])
+
+	return pseudonymEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// StringUpper returns the base32-encoded representation of the ID like String, but with its letters
+// in upper case, e.g. for DNS labels or case-insensitive filesystems. The strings sort the same way
+// as their canonical counterparts do.
+//
+// The representation is non-canonical. It must be decoded using FromEncodedStringInsensitive.
+func (id ID) StringUpper() string {
+	enc := internal.Encode((*[10]byte)(&id))
+	for i, c := range enc {
+		if c >= 'a' {
+			enc[i] = c - ('a' - 'A')
+		}
+	}
+
+	dst := enc[:]
+
+	return *(*string)(unsafe.Pointer(&dst))
+}
+
+// ShortestUnique returns the shortest prefix of the encoded representation of the ID which
+// tells it apart from all the other IDs in the given set. The set may, but does not have to,
+// include the ID itself.
+//
+// See MinUniquePrefixLen to determine a common length for displaying an entire set instead.
+func (id ID) ShortestUnique(set []ID) string {
+	var (
+		enc = id.String()
+		n   int
+	)
+
+	for i := range set {
+		if set[i] == id {
+			continue
+		}
+
+		if l := commonPrefixLen(enc, set[i].String()); l > n {
+			n = l
+		}
+	}
+
+	// Note: IDs which differ have at least one differing character in their encoded form,
+	// so at this point n can't exceed SizeEncoded-1.
+	return enc[:n+1]
+}
+
+// InvertedString returns a base32-encoded representation of the ID with its timestamp inverted
+// (all of its bits flipped, including the tick-tock bit), so that ascending sorts of such strings
+// yield the newest IDs first - e.g. for descending indexes in stores which can only sort strings
+// in ascending order. IDs with identical timestamps retain their relative order.
+//
+// The representation is non-canonical. It must be decoded using FromInvertedString - decoding it
+// as a canonical representation silently results in a different ID.
+func (id ID) InvertedString() string {
+	for i := 0; i < 5; i++ {
+		id[i] = ^id[i]
+	}
+
+	return id.String()
+}
+
+// Decimal returns the ID, interpreted as an unsigned 80-bit big-endian integer, as a base-10 string
+// of up to 25 digits, e.g. for legacy systems which only accept numeric IDs. It must be parsed using
+// FromDecimal.
+//
+// The digits are not padded, so the strings do not sort like the IDs - see DecimalPadded.
+func (id ID) Decimal() string {
+	q, r := id.decimal()
+	if q == 0 {
+		return strconv.FormatUint(r, 10)
+	}
+
+	return strconv.FormatUint(q, 10) + padDecimal(strconv.FormatUint(r, 10), 19)
+}
+
+// DecimalPadded returns the ID like Decimal does, but zero-padded to a fixed width of 25 digits,
+// so that the strings sort like the IDs.
+func (id ID) DecimalPadded() string {
+	q, r := id.decimal()
+
+	return padDecimal(strconv.FormatUint(q, 10), 6) + padDecimal(strconv.FormatUint(r, 10), 19)
+}
+
+// decimal splits the 80-bit value of the ID into its quotient and remainder of a division
+// by 10^19 - the highest power of 10 that fits in a uint64.
+func (id ID) decimal() (q, r uint64) {
+	return bits.Div64(uint64(binary.BigEndian.Uint16(id[:])), binary.BigEndian.Uint64(id[2:]), 1e19)
+}
+
+// base62 is the alphabet of the base-62 representation, in ASCII order.
+const base62 = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// Base62 returns the ID, interpreted as an unsigned 80-bit big-endian integer, as a base-62 string
+// (digits, then upper-case and lower-case ASCII letters), e.g. for URLs which need to be shorter than
+// the canonical encoding allows. It must be parsed using FromBase62.
+//
+// The strings are of variable length - up to 14 characters - as leading zero digits get omitted (as with
+// Decimal), so they do NOT sort like the IDs do. Base62 is meant for opaque identifiers, not for range scans.
+func (id ID) Base62() string {
+	var (
+		buf = [14]byte{}
+		i   = len(buf)
+		hi  = uint64(binary.BigEndian.Uint16(id[:]))
+		lo  = binary.BigEndian.Uint64(id[2:])
+		r   uint64
+	)
+
+	for {
+		// (hi, lo), r = (hi, lo) / 62, (hi, lo) % 62, where hi holds the top 16 bits.
+		hi, r = hi/62, hi%62
+		lo, r = bits.Div64(r, lo, 62)
+
+		i--
+		buf[i] = base62[r]
+
+		if hi == 0 && lo == 0 {
+			break
+		}
+	}
+
+	return string(buf[i:])
+}
+
+func padDecimal(s string, width int) string {
+	if len(s) >= width {
+		return s
+	}
+
+	return strings.Repeat("0", width-len(s)) + s
+}
+
 // Bytes returns the ID as a byte slice.
 func (id ID) Bytes() []byte {
 	return id[:]
 }
 
+// LittleEndian returns the ID with both of its 40-bit blocks (the timestamp and the payload) byte-swapped
+// into little-endian order, for interop with storage systems or legacy readers which expect little-endian
+// fields. The ID can be restored using FromLittleEndian.
+//
+// The little-endian form does NOT preserve the sort order of IDs - comparing two of them byte-wise
+// does not order them the way their IDs do.
+func (id ID) LittleEndian() (b [SizeBinary]byte) {
+	b[0], b[1], b[2], b[3], b[4] = id[4], id[3], id[2], id[1], id[0]
+	b[5], b[6], b[7], b[8], b[9] = id[9], id[8], id[7], id[6], id[5]
+
+	return
+}
+
 // MarshalBinary implements encoding.BinaryMarshaler by returning the ID as a byte slice.
 func (id ID) MarshalBinary() ([]byte, error) {
 	return id[:], nil
 }
 
+// AppendBinary implements encoding.BinaryAppender by appending the ID to dst, i.e. its 10 bytes as
+// returned by Bytes, e.g. for serializers batching many IDs into one buffer without a slice per ID.
+// The error is always nil - it only exists to satisfy the interface.
+func (id ID) AppendBinary(dst []byte) ([]byte, error) {
+	return append(dst, id[:]...), nil
+}
+
 // UnmarshalBinary implements encoding.BinaryUnmarshaler by copying src into the receiver.
 func (id *ID) UnmarshalBinary(src []byte) error {
 	if len(src) != SizeBinary {
@@ -119,6 +614,20 @@ func (id *ID) UnmarshalBinary(src []byte) error {
 	return nil
 }
 
+// GobEncode implements gob.GobEncoder by returning the ID as a byte slice, i.e. in its 10 byte
+// binary representation - regardless of which of the other marshaling interfaces gob would
+// otherwise pick up.
+func (id ID) GobEncode() ([]byte, error) {
+	return id[:], nil
+}
+
+// GobDecode implements gob.GobDecoder by copying src into the receiver.
+//
+// The slice must have a length of 10. Returns a InvalidDataSizeError if it does not.
+func (id *ID) GobDecode(src []byte) error {
+	return id.UnmarshalBinary(src)
+}
+
 // MarshalText implements encoding.TextMarshaler by returning the base32-encoded representation
 // of the ID as a byte slice.
 func (id ID) MarshalText() ([]byte, error) {
@@ -127,6 +636,21 @@ func (id ID) MarshalText() ([]byte, error) {
 	return b[:], nil
 }
 
+// AppendEncoded appends the base32-encoded representation of the ID to dst and returns the extended
+// buffer, e.g. for encoding IDs in a loop into a reused scratch buffer. Unlike String and MarshalText,
+// it does not allocate - as long as dst has room for SizeEncoded more bytes.
+func (id ID) AppendEncoded(dst []byte) []byte {
+	enc := internal.Encode((*[10]byte)(&id))
+
+	return append(dst, enc[:]...)
+}
+
+// AppendText implements encoding.TextAppender by appending the base32-encoded representation
+// of the ID to dst. See AppendEncoded.
+func (id ID) AppendText(dst []byte) ([]byte, error) {
+	return id.AppendEncoded(dst), nil
+}
+
 // UnmarshalText implements encoding.TextUnmarshaler by decoding a base32-encoded representation
 // of the ID from src into the receiver.
 func (id *ID) UnmarshalText(src []byte) error {
@@ -183,6 +707,95 @@ func (id *ID) UnmarshalJSON(src []byte) error {
 	return nil
 }
 
+// EmptyStringID wraps an ID to provide a JSON representation in which the zero ID is an empty
+// string ("") instead of null, for APIs which do not accept null in place of a string.
+//
+// Aside from the zero value, the representation is identical to that of an ID, e.g. a field
+// declared as...
+//	ID sno.EmptyStringID `json:"id"`
+// ... is interchangeable with a sno.ID field as long as it does not hold a zero ID.
+type EmptyStringID ID
+
+// MarshalJSON implements encoding.json.Marshaler by returning the base32-encoded and quoted
+// representation of the ID as a byte slice.
+//
+// If the ID is a zero value, MarshalJSON will return a byte slice containing an empty quoted
+// string ("") instead.
+func (id EmptyStringID) MarshalJSON() ([]byte, error) {
+	if ID(id) == zero {
+		return []byte("\"\""), nil
+	}
+
+	return ID(id).MarshalJSON()
+}
+
+// UnmarshalJSON implements encoding.json.Unmarshaler by decoding a base32-encoded and quoted
+// representation of an ID from src into the receiver.
+//
+// If the byte slice is an empty quoted string ("") or an unquoted 'null', the receiving ID
+// will instead be set to a zero ID.
+func (id *EmptyStringID) UnmarshalJSON(src []byte) error {
+	if len(src) == 2 && src[0] == '"' && src[1] == '"' {
+		*id = EmptyStringID(zero)
+		return nil
+	}
+
+	return (*ID)(id).UnmarshalJSON(src)
+}
+
+// VerboseID wraps an ID to provide a JSON representation as an object with the ID's components
+// decoded alongside it, e.g. for admin and debugging endpoints:
+//	{"id":"brpk4q72xwf2m63l","time":"2031-05-08T23:03:34.976Z","partition":39434,"meta":255,"sequence":4147}
+//
+// Only the "id" field gets read when unmarshaling - the components are informational.
+type VerboseID ID
+
+type verboseID struct {
+	ID        ID     `json:"id"`
+	Time      string `json:"time"`
+	Partition uint16 `json:"partition"`
+	Meta      byte   `json:"meta"`
+	Sequence  uint16 `json:"sequence"`
+}
+
+// MarshalJSON implements encoding.json.Marshaler by returning a JSON object containing the base32-encoded
+// representation of the ID along with its decoded time (RFC 3339, UTC), partition, metabyte and sequence.
+//
+// If the ID is a zero value, MarshalJSON will return a byte slice containing 'null' (unquoted) instead.
+func (id VerboseID) MarshalJSON() ([]byte, error) {
+	if ID(id) == zero {
+		return []byte("null"), nil
+	}
+
+	v := ID(id)
+
+	return json.Marshal(verboseID{
+		ID:        v,
+		Time:      v.Time().UTC().Format(time.RFC3339Nano),
+		Partition: v.Partition().AsUint16(),
+		Meta:      v.Meta(),
+		Sequence:  v.Sequence(),
+	})
+}
+
+// UnmarshalJSON implements encoding.json.Unmarshaler by decoding the "id" field of a JSON object
+// into the receiver. All other fields are ignored.
+//
+// If the byte slice is an unquoted 'null', the receiving ID will instead be set to a zero ID.
+func (id *VerboseID) UnmarshalJSON(src []byte) error {
+	var v struct {
+		ID ID `json:"id"`
+	}
+
+	if err := json.Unmarshal(src, &v); err != nil {
+		return err
+	}
+
+	*id = VerboseID(v.ID)
+
+	return nil
+}
+
 // Compare returns an integer comparing this and that ID lexicographically.
 //
 // Returns:
@@ -197,6 +810,49 @@ func (id ID) Compare(that ID) int {
 	return bytes.Compare(id[:], that[:])
 }
 
+// IDDelta describes the differences between the components of two IDs. See ID.Sub.
+type IDDelta struct {
+	Timeframes  int64 // Difference between the timestamps, in time units (4msec each).
+	Partition   int32 // Difference between the partitions, as expressed as uint16s.
+	Sequence    int32 // Difference between the sequences.
+	MetaDiffers bool  // Whether the metabytes differ.
+	TickDiffers bool  // Whether the tick-tock bits differ.
+}
+
+// IsZero checks whether the delta describes two identical IDs.
+func (d IDDelta) IsZero() bool {
+	return d == IDDelta{}
+}
+
+// String implements fmt.Stringer by returning a human-readable description of the delta, e.g.:
+//	timeframes: +3, partition: 0, sequence: -12, meta: same, tick: differs
+func (d IDDelta) String() string {
+	same := func(differs bool) string {
+		if differs {
+			return "differs"
+		}
+
+		return "same"
+	}
+
+	return fmt.Sprintf("timeframes: %+d, partition: %+d, sequence: %+d, meta: %s, tick: %s",
+		d.Timeframes, d.Partition, d.Sequence, same(d.MetaDiffers), same(d.TickDiffers))
+}
+
+// Sub returns the differences between the components of the ID and another ID, as id - other.
+//
+// Meant for investigating why two IDs differ (e.g. in tests and while debugging) - to simply
+// compare IDs, use ID.Compare or the == operator instead.
+func (id ID) Sub(other ID) IDDelta {
+	return IDDelta{
+		Timeframes:  int64(binary.BigEndian.Uint64(id[:])>>25) - int64(binary.BigEndian.Uint64(other[:])>>25),
+		Partition:   int32(id.Partition().AsUint16()) - int32(other.Partition().AsUint16()),
+		Sequence:    int32(id.Sequence()) - int32(other.Sequence()),
+		MetaDiffers: id.Meta() != other.Meta(),
+		TickDiffers: id[4]&1 != other[4]&1,
+	}
+}
+
 // Value implements the sql.driver.Valuer interface by returning the ID as a byte slice.
 // If you'd rather receive a string, wrapping an ID is a possible solution...
 //
@@ -263,3 +919,52 @@ func (id *ID) Scan(value interface{}) error {
 
 	return nil
 }
+
+// checksum returns the CRC-8 (polynomial 0x07) over all bytes of the ID but its metabyte.
+func (id *ID) checksum() (crc byte) {
+	for i := range id {
+		if i != 5 {
+			crc = crc8Table[crc^id[i]]
+		}
+	}
+
+	return
+}
+
+// crc8Table is the lookup table for CRC-8 with a polynomial of 0x07.
+var crc8Table = func() (t [256]byte) {
+	for i := range t {
+		c := byte(i)
+		for j := 0; j < 8; j++ {
+			if c&0x80 != 0 {
+				c = c<<1 ^ 0x07
+			} else {
+				c <<= 1
+			}
+		}
+
+		t[i] = c
+	}
+
+	return
+}()
+
+// fnv32a returns the 32-bit FNV-1a hash of src.
+func fnv32a(src []byte) uint32 {
+	h := uint32(2166136261)
+	for _, c := range src {
+		h ^= uint32(c)
+		h *= 16777619
+	}
+
+	return h
+}
+
+// commonPrefixLen returns the length of the longest common prefix of a and b.
+func commonPrefixLen(a, b string) (n int) {
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+
+	return
+}
@@ -2,8 +2,13 @@ package sno
 
 import (
 	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -50,6 +55,39 @@ func TestID_Timestamp(t *testing.T) {
 	}
 }
 
+func TestID_Age(t *testing.T) {
+	if age := New(255).Age(); age < 0 || age > time.Second {
+		t.Errorf("expected age of a fresh ID to be in range [0, 1s], got [%v]", age)
+	}
+
+	// Account for the 4msec resolution of timestamps.
+	age := NewWithTime(255, time.Now().Add(-time.Hour)).Age()
+	if age < time.Hour || age > time.Hour+time.Second {
+		t.Errorf("expected age in range [1h, 1h1s], got [%v]", age)
+	}
+
+	if age := NewWithTime(255, time.Now().Add(time.Hour)).Age(); age >= 0 {
+		t.Errorf("expected negative age of an ID from the future, got [%v]", age)
+	}
+}
+
+func TestID_OlderThan(t *testing.T) {
+	fresh := New(255)
+	old := NewWithTime(255, time.Now().Add(-time.Hour))
+
+	if fresh.OlderThan(time.Minute) {
+		t.Error("expected fresh ID to not be older than 1m")
+	}
+
+	if !old.OlderThan(time.Minute) {
+		t.Error("expected old ID to be older than 1m")
+	}
+
+	if old.OlderThan(2 * time.Hour) {
+		t.Error("expected old ID to not be older than 2h")
+	}
+}
+
 func TestID_Meta(t *testing.T) {
 	var expected byte = 255
 	id := New(expected)
@@ -61,8 +99,8 @@ func TestID_Meta(t *testing.T) {
 }
 
 func TestID_Partition(t *testing.T) {
-	expected := generator.Partition()
-	actual := generator.New(255).Partition()
+	expected := defaultGenerator().Partition()
+	actual := defaultGenerator().New(255).Partition()
 
 	if actual != expected {
 		t.Errorf("expected [%v], got [%v]", expected, actual)
@@ -70,14 +108,288 @@ func TestID_Partition(t *testing.T) {
 }
 
 func TestID_Sequence(t *testing.T) {
-	expected := atomic.LoadUint32(&generator.seq) + 1
-	actual := generator.New(255).Sequence()
+	expected := atomic.LoadUint32(&defaultGenerator().seq) + 1
+	actual := defaultGenerator().New(255).Sequence()
 
 	if actual != uint16(expected) {
 		t.Errorf("expected [%v], got [%v]", expected, actual)
 	}
 }
 
+func TestID_WithMeta(t *testing.T) {
+	var (
+		src = ID{78, 111, 33, 96, 160, 255, 154, 10, 16, 51}
+		id  = src.WithMeta(7)
+	)
+
+	if actual, expected := id.Meta(), byte(7); actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	if id.Timestamp() != src.Timestamp() || id.Partition() != src.Partition() || id.Sequence() != src.Sequence() {
+		t.Errorf("expected components other than the metabyte to be untouched, got [%v] from [%v]", id[:], src[:])
+	}
+
+	if actual, expected := src, (ID{78, 111, 33, 96, 160, 255, 154, 10, 16, 51}); actual != expected {
+		t.Errorf("expected the original to remain [%v], got [%v]", expected, actual)
+	}
+}
+
+func TestID_WithPartition(t *testing.T) {
+	var (
+		src = ID{78, 111, 33, 96, 160, 255, 154, 10, 16, 51}
+		id  = src.WithPartition(Partition{'W', 'P'})
+	)
+
+	if actual, expected := id.Partition(), (Partition{'W', 'P'}); actual != expected {
+		t.Errorf("expected [%v], got [%v]", expected, actual)
+	}
+
+	if id.Timestamp() != src.Timestamp() || id.Meta() != src.Meta() || id.Sequence() != src.Sequence() {
+		t.Errorf("expected components other than the partition to be untouched, got [%v] from [%v]", id[:], src[:])
+	}
+
+	if actual, expected := src, (ID{78, 111, 33, 96, 160, 255, 154, 10, 16, 51}); actual != expected {
+		t.Errorf("expected the original to remain [%v], got [%v]", expected, actual)
+	}
+
+	if n := testing.AllocsPerRun(100, func() { id = src.WithMeta(1).WithPartition(Partition{1, 2}) }); n != 0 {
+		t.Errorf("expected no allocations, got [%v]", n)
+	}
+}
+
+func TestID_SchemaSplit(t *testing.T) {
+	id := ID{78, 111, 33, 96, 160, 0xB1, 154, 10, 16, 51}
+
+	for _, c := range []struct {
+		bits    uint8
+		version uint8
+		payload uint8
+	}{
+		{0, 5, 17}, // DefaultSchemaVersionBits.
+		{1, 1, 49},
+		{3, 5, 17},
+		{5, 22, 1},
+		{8, 0xB1, 0},
+	} {
+		version, payload := id.SchemaSplit(c.bits)
+		if version != c.version || payload != c.payload {
+			t.Errorf("%d: expected [%d/%d], got [%d/%d]", c.bits, c.version, c.payload, version, payload)
+		}
+	}
+
+	if actual, expected := id.SchemaVersion(), uint8(5); actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	if actual, expected := id.SchemaPayload(), uint8(17); actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	defer func() {
+		if actual, expected := recover(), (&InvalidSchemaVersionBitsError{Bits: 9}); !reflect.DeepEqual(actual, expected) {
+			t.Errorf("expected panic with [%v], got [%v]", expected, actual)
+		}
+	}()
+
+	id.SchemaSplit(9)
+}
+
+func TestID_WithSchemaVersion(t *testing.T) {
+	src := ID{78, 111, 33, 96, 160, 0xB1, 154, 10, 16, 51} // Version 5, payload 17 with the default split.
+
+	// To a new version within the same split.
+	id, err := src.WithSchemaVersion(6, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if version, payload := id.SchemaSplit(0); version != 6 || payload != 17 {
+		t.Errorf("expected [6/17], got [%d/%d]", version, payload)
+	}
+
+	if id.Timestamp() != src.Timestamp() || id.Partition() != src.Partition() || id.Sequence() != src.Sequence() {
+		t.Errorf("expected components other than the metabyte to be untouched, got [%v] from [%v]", id[:], src[:])
+	}
+
+	// To a new split, keeping the payload.
+	if id, err = src.WithSchemaVersion(3, 3, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	if version, payload := id.SchemaSplit(2); version != 3 || payload != 17 {
+		t.Errorf("expected [3/17], got [%d/%d]", version, payload)
+	}
+
+	for _, c := range []struct {
+		version uint8
+		bits    uint8
+		toBits  uint8
+		err     error
+	}{
+		{8, 3, 3, &InvalidSchemaVersionError{Version: 8, Payload: 17, Bits: 3}},
+		{1, 3, 5, &InvalidSchemaVersionError{Version: 1, Payload: 17, Bits: 5}}, // Payload does not fit 3 bits.
+		{1, 9, 3, &InvalidSchemaVersionBitsError{Bits: 9}},
+		{1, 3, 9, &InvalidSchemaVersionBitsError{Bits: 9}},
+	} {
+		id, err := src.WithSchemaVersion(c.version, c.bits, c.toBits)
+		if !reflect.DeepEqual(err, c.err) {
+			t.Errorf("%d/%d/%d: expected [%v], got [%v]", c.version, c.bits, c.toBits, c.err, err)
+		}
+
+		if !id.IsZero() {
+			t.Errorf("%d/%d/%d: expected zero ID, got [%s]", c.version, c.bits, c.toBits, id)
+		}
+	}
+}
+
+func TestID_Seed24(t *testing.T) {
+	src := ID{78, 111, 33, 96, 160, 255, 154, 10, 16, 51}
+
+	// Must remain stable across versions, as seeds are likely to be persisted or shared
+	// across frontends.
+	if actual, expected := src.Seed24(), uint32(2451165); actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	// Only the payload gets taken into account.
+	other := src
+	other[0]++
+
+	if actual, expected := other.Seed24(), src.Seed24(); actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	other[9]++
+
+	if other.Seed24() == src.Seed24() {
+		t.Errorf("expected seeds of IDs with differing payloads to differ")
+	}
+
+	for i := 0; i < 1024; i++ {
+		if seed := New(byte(i)).Seed24(); seed > 0xFFFFFF {
+			t.Errorf("expected seed to fit in 24 bits, got [%d]", seed)
+		}
+	}
+}
+
+func TestID_MaybeUserTimed(t *testing.T) {
+	start := time.Now()
+
+	if id := New(255); id.MaybeUserTimed(start) {
+		t.Errorf("expected ID [%s] generated via New to not be reported", id)
+	}
+
+	if id := NewWithTime(255, start.Add(-24*time.Hour)); !id.MaybeUserTimed(start) {
+		t.Errorf("expected ID [%s] from before the window to be reported", id)
+	}
+
+	if id := NewWithTime(255, start.Add(24*time.Hour)); !id.MaybeUserTimed(start) {
+		t.Errorf("expected ID [%s] from the future to be reported", id)
+	}
+
+	// Tick-tocked IDs can't come from NewWithTime.
+	id := NewWithTime(255, start.Add(-24*time.Hour))
+	id[4] |= 1
+
+	if id.MaybeUserTimed(start) {
+		t.Errorf("expected tick-tocked ID [%s] to not be reported", id)
+	}
+}
+
+func TestID_Shard(t *testing.T) {
+	const (
+		n      = 8
+		sample = 8192
+	)
+
+	g, err := NewGenerator(&GeneratorSnapshot{
+		Partition: Partition{'S', 'S'},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	counts := make([]int, n)
+
+	for i := 0; i < sample; i++ {
+		id := g.New(255)
+
+		shard := id.Shard(n)
+		if shard < 0 || shard >= n {
+			t.Fatalf("expected shard in range [0, %d), got [%d]", n, shard)
+		}
+
+		if actual, expected := id.Shard(n), shard; actual != expected {
+			t.Fatalf("expected stable shard [%d], got [%d]", expected, actual)
+		}
+
+		counts[shard]++
+	}
+
+	// Allow for a deviation of 25% from a perfectly uniform distribution.
+	for shard, count := range counts {
+		if count < sample/n*3/4 || count > sample/n*5/4 {
+			t.Errorf("shard %d: expected roughly [%d] IDs, got [%d]", shard, sample/n, count)
+		}
+	}
+
+	defer func() {
+		err, ok := recover().(*InvalidSizeError)
+		if !ok {
+			t.Fatalf("expected a panic with [%T]", &InvalidSizeError{})
+		}
+
+		if actual, expected := err.Func, "Shard"; actual != expected {
+			t.Errorf("expected [%s], got [%s]", expected, actual)
+		}
+	}()
+
+	New(255).Shard(0)
+}
+
+func TestID_VerifyChecksum(t *testing.T) {
+	g, err := NewGenerator(&GeneratorSnapshot{
+		Partition: Partition{'C', 'S'},
+		Checksum:  true,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := []ID{
+		g.New(255),
+		g.New(0),
+		g.NewWithTime(255, time.Now().Add(-time.Hour)),
+		g.NewForPartition(255, Partition{1, 2}),
+		g.NewFromContent([]byte("content")),
+	}
+
+	for i, id := range ids {
+		if !id.VerifyChecksum() {
+			t.Errorf("%d: expected checksum of [%s] to verify", i, id)
+		}
+
+		// CRC-8 detects all single-bit errors, regardless of which byte they are in.
+		for j := range id {
+			for bit := uint(0); bit < 8; bit++ {
+				corrupted := id
+				corrupted[j] ^= 1 << bit
+
+				if corrupted.VerifyChecksum() {
+					t.Errorf("%d: expected checksum of [%s] with bit %d of byte %d flipped to fail", i, id, bit, j)
+				}
+			}
+		}
+	}
+
+	// Known check value of CRC-8 (polynomial 0x07) over the ASCII digits 1-9.
+	id := ID{'1', '2', '3', '4', '5', 0, '6', '7', '8', '9'}
+	if actual, expected := id.checksum(), byte(0xF4); actual != expected {
+		t.Errorf("expected [%#x], got [%#x]", expected, actual)
+	}
+}
+
 func TestID_String(t *testing.T) {
 	src := ID{78, 111, 33, 96, 160, 255, 154, 10, 16, 51}
 	expected := "brpk4q72xwf2m63l"
@@ -88,6 +400,77 @@ func TestID_String(t *testing.T) {
 	}
 }
 
+func TestID_InvertedString(t *testing.T) {
+	var (
+		tn  = time.Now()
+		ids = make([]ID, 8)
+	)
+
+	for i := range ids {
+		ids[i] = NewWithTime(255, tn.Add(time.Duration(i)*time.Second))
+	}
+
+	canonical := make([]string, len(ids))
+	inverted := make([]string, len(ids))
+
+	for i := range ids {
+		canonical[i] = ids[i].String()
+		inverted[i] = ids[i].InvertedString()
+
+		if inverted[i] == canonical[i] {
+			t.Errorf("%d: expected inverted representation to differ from the canonical one", i)
+		}
+
+		actual, err := FromInvertedString(inverted[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if actual != ids[i] {
+			t.Errorf("%d: expected [%v], got [%v]", i, ids[i], actual)
+		}
+	}
+
+	if !sort.StringsAreSorted(canonical) {
+		t.Error("expected canonical representations to sort oldest-first")
+	}
+
+	for i := 1; i < len(inverted); i++ {
+		if inverted[i-1] <= inverted[i] {
+			t.Errorf("%d: expected [%s] to sort after [%s]", i, inverted[i-1], inverted[i])
+		}
+	}
+}
+
+func TestID_ShortestUnique(t *testing.T) {
+	var (
+		id  = mustDecode(t, "brpk4q72xwf2m63l")
+		set = []ID{
+			id,
+			mustDecode(t, "b2222222xwf2m63l"),
+			mustDecode(t, "brpkxxxxxxxxxxxx"),
+		}
+	)
+
+	for _, c := range []struct {
+		name string
+		set  []ID
+		out  string
+	}{
+		{"empty", nil, "b"},
+		{"self", set[:1], "b"},
+		{"overlapping", set, "brpk4"},
+		{"near-identical", append(set, mustDecode(t, "brpk4q72xwf2m63m")), "brpk4q72xwf2m63l"},
+	} {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			if actual, expected := id.ShortestUnique(c.set), c.out; actual != expected {
+				t.Errorf("expected [%s], got [%s]", expected, actual)
+			}
+		})
+	}
+}
+
 func TestID_Bytes(t *testing.T) {
 	src := ID{78, 111, 33, 96, 160, 255, 154, 10, 16, 51}
 	expected := make([]byte, SizeBinary)
@@ -104,6 +487,34 @@ func TestID_Bytes(t *testing.T) {
 	}
 }
 
+func TestID_AppendBinary(t *testing.T) {
+	src := ID{78, 111, 33, 96, 160, 255, 154, 10, 16, 51}
+
+	actual, err := src.AppendBinary(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := src.Bytes(); !bytes.Equal(actual, expected) {
+		t.Errorf("expected [%v], got [%v]", expected, actual)
+	}
+
+	// Length-prefixed records appended into a single buffer.
+	var buf []byte
+	for i := 0; i < 2; i++ {
+		buf = append(buf, SizeBinary)
+		buf, _ = src.AppendBinary(buf)
+	}
+
+	if actual, expected := len(buf), 2*(SizeBinary+1); actual != expected {
+		t.Fatalf("expected [%d], got [%d]", expected, actual)
+	}
+
+	if id, err := FromBinaryBytes(buf[SizeBinary+2:]); err != nil || id != src {
+		t.Errorf("expected [%v], got [%v] (%v)", src, id, err)
+	}
+}
+
 func TestID_MarshalText(t *testing.T) {
 	src := ID{78, 111, 33, 96, 160, 255, 154, 10, 16, 51}
 	expected := []byte("brpk4q72xwf2m63l")
@@ -118,6 +529,28 @@ func TestID_MarshalText(t *testing.T) {
 	}
 }
 
+func TestID_AppendEncoded(t *testing.T) {
+	src := ID{78, 111, 33, 96, 160, 255, 154, 10, 16, 51}
+
+	if actual, expected := src.AppendEncoded([]byte("id=")), []byte("id=brpk4q72xwf2m63l"); !bytes.Equal(actual, expected) {
+		t.Errorf("expected [%s], got [%s]", expected, actual)
+	}
+
+	actual, err := src.AppendText(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, _ := src.MarshalText(); !bytes.Equal(actual, expected) {
+		t.Errorf("expected [%s], got [%s]", expected, actual)
+	}
+
+	buf := make([]byte, 0, SizeEncoded)
+	if n := testing.AllocsPerRun(100, func() { buf = src.AppendEncoded(buf[:0]) }); n != 0 {
+		t.Errorf("expected no allocations, got [%v]", n)
+	}
+}
+
 func TestID_UnmarshalText_Valid(t *testing.T) {
 	actual := ID{}
 	expected := ID{78, 111, 33, 96, 160, 255, 154, 10, 16, 51}
@@ -206,6 +639,82 @@ func TestID_UnmarshalJSON_Null(t *testing.T) {
 	}
 }
 
+func TestEmptyStringID_MarshalJSON(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		in   EmptyStringID
+		out  []byte
+	}{
+		{"valid", EmptyStringID{78, 111, 33, 96, 160, 255, 154, 10, 16, 51}, []byte("\"brpk4q72xwf2m63l\"")},
+		{"zero", EmptyStringID{}, []byte("\"\"")},
+	} {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			actual, err := c.in.MarshalJSON()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if expected := c.out; !bytes.Equal(actual, expected) {
+				t.Errorf("expected [%s], got [%s]", expected, actual)
+			}
+		})
+	}
+}
+
+func TestEmptyStringID_UnmarshalJSON(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		in   []byte
+		out  EmptyStringID
+		err  error
+	}{
+		{"valid", []byte("\"brpk4q72xwf2m63l\""), EmptyStringID{78, 111, 33, 96, 160, 255, 154, 10, 16, 51}, nil},
+		{"empty", []byte("\"\""), EmptyStringID{}, nil},
+		{"null", []byte("null"), EmptyStringID{}, nil},
+		{"invalid", []byte("\"brpk4q72\""), EmptyStringID{}, &InvalidDataSizeError{Size: 10}},
+	} {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			var actual EmptyStringID
+
+			err := actual.UnmarshalJSON(c.in)
+			if actual, expected := reflect.TypeOf(err), reflect.TypeOf(c.err); actual != expected {
+				t.Errorf("expected error type [%s], got [%s]", expected, actual)
+			}
+
+			if expected := c.out; actual != expected {
+				t.Errorf("expected [%v], got [%v]", expected, actual)
+			}
+		})
+	}
+}
+
+func TestEmptyStringID_JSON_RoundTrip(t *testing.T) {
+	type record struct {
+		Null  ID            `json:"null"`
+		Empty EmptyStringID `json:"empty"`
+	}
+
+	for _, id := range []ID{New(255), {}} {
+		src := record{Null: id, Empty: EmptyStringID(id)}
+
+		b, err := json.Marshal(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var actual record
+		if err := json.Unmarshal(b, &actual); err != nil {
+			t.Fatal(err)
+		}
+
+		if expected := src; actual != expected {
+			t.Errorf("expected [%v], got [%v]", expected, actual)
+		}
+	}
+}
+
 func TestID_IsZero(t *testing.T) {
 	for _, c := range []struct {
 		id   ID
@@ -247,6 +756,44 @@ func TestID_Compare(t *testing.T) {
 	}
 }
 
+func TestID_Sub(t *testing.T) {
+	var (
+		a = ID{78, 111, 33, 96, 160, 255, 154, 10, 16, 51}
+		b = a
+	)
+
+	if delta := a.Sub(b); !delta.IsZero() {
+		t.Errorf("expected zero delta, got [%s]", delta)
+	}
+
+	// 3 time units later, tick-tocked, different meta, previous partition and a lower sequence.
+	b[4] += 3<<1 | 1
+	b[5] = 0
+	b[7]--
+	b[9] -= 12
+
+	delta := b.Sub(a)
+	expected := IDDelta{
+		Timeframes:  3,
+		Partition:   -1,
+		Sequence:    -12,
+		MetaDiffers: true,
+		TickDiffers: true,
+	}
+
+	if delta != expected {
+		t.Errorf("expected [%s], got [%s]", expected, delta)
+	}
+
+	if actual, expected := delta.String(), "timeframes: +3, partition: -1, sequence: -12, meta: differs, tick: differs"; actual != expected {
+		t.Errorf("expected [%s], got [%s]", expected, actual)
+	}
+
+	if actual, expected := a.Sub(b).String(), "timeframes: -3, partition: +1, sequence: +12, meta: differs, tick: differs"; actual != expected {
+		t.Errorf("expected [%s], got [%s]", expected, actual)
+	}
+}
+
 func TestID_Value(t *testing.T) {
 	src := ID{78, 111, 33, 96, 160, 255, 154, 10, 16, 51}
 	expected := make([]byte, SizeBinary)
@@ -312,3 +859,524 @@ func TestID_Scan(t *testing.T) {
 		})
 	}
 }
+
+func TestVerboseID_MarshalJSON(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		in   VerboseID
+		out  string
+	}{
+		{
+			"valid",
+			VerboseID{78, 111, 33, 96, 160, 255, 154, 10, 16, 51},
+			`{"id":"brpk4q72xwf2m63l","time":"2031-05-08T23:03:34.976Z","partition":39434,"meta":255,"sequence":4147}`,
+		},
+		{"zero", VerboseID{}, "null"},
+	} {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			actual, err := json.Marshal(c.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if expected := c.out; string(actual) != expected {
+				t.Errorf("expected [%s], got [%s]", expected, actual)
+			}
+		})
+	}
+}
+
+func TestVerboseID_JSON_RoundTrip(t *testing.T) {
+	for _, id := range []ID{New(255), {}} {
+		b, err := json.Marshal(VerboseID(id))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var actual VerboseID
+		if err := json.Unmarshal(b, &actual); err != nil {
+			t.Fatal(err)
+		}
+
+		if expected := VerboseID(id); actual != expected {
+			t.Errorf("expected [%v], got [%v]", expected, actual)
+		}
+	}
+
+	// Only the id field matters - everything else gets ignored.
+	var actual VerboseID
+	if err := json.Unmarshal([]byte(`{"time":"invalid","id":"brpk4q72xwf2m63l","meta":0}`), &actual); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := (VerboseID{78, 111, 33, 96, 160, 255, 154, 10, 16, 51}); actual != expected {
+		t.Errorf("expected [%v], got [%v]", expected, actual)
+	}
+
+	err := json.Unmarshal([]byte(`{"id":"brpk4q72"}`), &actual)
+	if actual, expected := reflect.TypeOf(err), reflect.TypeOf(&InvalidDataSizeError{}); actual != expected {
+		t.Errorf("expected error type [%s], got [%s]", expected, actual)
+	}
+}
+
+func TestID_PrecisionLoss(t *testing.T) {
+	tn := time.Now()
+
+	for _, offset := range []time.Duration{0, 1, TimeUnit / 2, TimeUnit - 1, TimeUnit, TimeUnit + 1} {
+		var (
+			in  = tn.Add(offset)
+			id  = NewWithTime(255, in)
+			err = in.Sub(id.Time())
+		)
+
+		if actual, expected := id.PrecisionLoss(), time.Duration(TimeUnit); actual != expected {
+			t.Errorf("expected [%s], got [%s]", expected, actual)
+		}
+
+		if err < 0 || err >= id.PrecisionLoss() {
+			t.Errorf("%s: expected error in range [0, %s), got [%s]", offset, id.PrecisionLoss(), err)
+		}
+	}
+}
+
+func TestID_PossibleWallTimeRange(t *testing.T) {
+	var (
+		units = int64(1e10)
+		at    = func(units int64) time.Time {
+			return time.Unix(0, units*TimeUnit+epochNsec)
+		}
+
+		// The Generator regressed from units+10 to units, tick-tocking.
+		snap = GeneratorSnapshot{
+			WallHi:   units + 1,
+			WallSafe: units + 10,
+			Drifts:   1,
+		}
+	)
+
+	for _, c := range []struct {
+		name     string
+		units    int64
+		tick     bool
+		earliest time.Time
+		latest   time.Time
+	}{
+		{"sound", units, false, at(units), at(units + 1).Add(-1)},
+		{"tick-tocked", units, true, at(units), at(units + 11).Add(-1)},
+		{"tick-tocked-above-safe", units + 20, true, at(units + 20), at(units + 21).Add(-1)},
+	} {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			var id ID
+			binary.BigEndian.PutUint64(id[:], uint64(c.units)<<25)
+			if c.tick {
+				id[4] |= 1
+			}
+
+			earliest, latest := id.PossibleWallTimeRange(snap)
+
+			if actual, expected := earliest, c.earliest; !actual.Equal(expected) {
+				t.Errorf("expected [%s], got [%s]", expected, actual)
+			}
+
+			if actual, expected := latest, c.latest; !actual.Equal(expected) {
+				t.Errorf("expected [%s], got [%s]", expected, actual)
+			}
+		})
+	}
+}
+
+func TestID_SeriesKey(t *testing.T) {
+	id := ID{78, 111, 33, 96, 161, 255, 154, 10, 16, 51}
+
+	if actual, expected := id.SeriesKey(), uint64(id.Timestamp()-epochNsec)/TimeUnit<<16|0x9A0A; actual != expected {
+		t.Errorf("expected [%x], got [%x]", expected, actual)
+	}
+
+	var (
+		tn  = time.Now()
+		ids = []ID{
+			NewWithTime(255, tn),
+			NewWithTime(0, tn),
+			NewWithTime(0, tn.Add(time.Second)),
+			NewWithTime(255, tn.Add(time.Second)),
+		}
+	)
+
+	// Same timeframe, different partitions - and the metabyte must not affect the order.
+	ids[0][6], ids[1][6] = 1, 2
+	ids[2][6], ids[3][6] = 1, 2
+
+	for i := 1; i < len(ids); i++ {
+		if ids[i-1].SeriesKey() >= ids[i].SeriesKey() {
+			t.Errorf("%d: expected [%x] to sort before [%x]", i, ids[i-1].SeriesKey(), ids[i].SeriesKey())
+		}
+	}
+
+	if ids[0].SeriesKey()>>55 != 0 {
+		t.Errorf("expected the top 9 bits to be zero, got [%x]", ids[0].SeriesKey())
+	}
+}
+
+func TestID_SequenceFraction(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		seq      uint16
+		min, max uint16
+		expected float64
+	}{
+		{"min", 1024, 1024, 2048, 0},
+		{"quarter", 1280, 1024, 2048, 0.25},
+		{"half", 1536, 1024, 2048, 0.5},
+		{"max", 2048, 1024, 2048, 1},
+		{"reversed-bounds", 1280, 2048, 1024, 0.25},
+		{"below-min", 0, 1024, 2048, 0},
+		{"above-max", MaxSequence, 1024, 2048, 1},
+		{"full-range", MaxSequence / 2, 0, MaxSequence - 1, 0.5},
+		{"identical-bounds", 1024, 1024, 1024, 0},
+	} {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			var id ID
+			id[8], id[9] = byte(c.seq>>8), byte(c.seq)
+
+			if actual, expected := id.SequenceFraction(c.min, c.max), c.expected; actual != expected {
+				t.Errorf("expected [%v], got [%v]", expected, actual)
+			}
+		})
+	}
+}
+
+func TestID_PartitionLocalKey(t *testing.T) {
+	id := ID{78, 111, 33, 96, 161, 255, 154, 10, 16, 51}
+
+	if actual, expected := id.PartitionLocalKey(), [8]byte{0, 78, 111, 33, 96, 161, 16, 51}; actual != expected {
+		t.Errorf("expected [%v], got [%v]", expected, actual)
+	}
+
+	g, err := NewGenerator(&GeneratorSnapshot{
+		Partition:   Partition{'A', 'B'},
+		SequenceMin: 65000,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Spans several timeframes and a sequence overflow.
+	ids := make([]ID, 4096)
+	for i := range ids {
+		ids[i] = g.New(255)
+	}
+
+	for i := 1; i < len(ids); i++ {
+		var (
+			a, b   = ids[i-1].PartitionLocalKey(), ids[i].PartitionLocalKey()
+			keyCmp = bytes.Compare(a[:], b[:])
+		)
+
+		if actual, expected := keyCmp, ids[i-1].Compare(ids[i]); actual != expected {
+			t.Errorf("%d: expected [%d], got [%d]", i, expected, actual)
+		}
+	}
+}
+
+func TestID_Decimal(t *testing.T) {
+	for _, c := range []struct {
+		name   string
+		in     ID
+		out    string
+		padded string
+	}{
+		{"zero", ID{}, "0", "0000000000000000000000000"},
+		{"one", ID{9: 1}, "1", "0000000000000000000000001"},
+		{"uint64-max", ID{2: 255, 255, 255, 255, 255, 255, 255, 255}, "18446744073709551615", "0000018446744073709551615"},
+		{"uint64-max+1", ID{1: 1}, "18446744073709551616", "0000018446744073709551616"},
+		{"max", MaxID(), "1208925819614629174706175", "1208925819614629174706175"},
+		{"valid", ID{78, 111, 33, 96, 160, 255, 154, 10, 16, 51}, "370394579355234764197939", "0370394579355234764197939"},
+	} {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			if actual, expected := c.in.Decimal(), c.out; actual != expected {
+				t.Errorf("expected [%s], got [%s]", expected, actual)
+			}
+
+			if actual, expected := c.in.DecimalPadded(), c.padded; actual != expected {
+				t.Errorf("expected [%s], got [%s]", expected, actual)
+			}
+
+			for _, s := range []string{c.out, c.padded} {
+				actual, err := FromDecimal(s)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if expected := c.in; actual != expected {
+					t.Errorf("expected [%v], got [%v]", expected, actual)
+				}
+			}
+		})
+	}
+}
+
+func TestID_Base62(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		in   ID
+		out  string
+	}{
+		{"zero", ID{}, "0"},
+		{"one", ID{9: 1}, "1"},
+		{"61", ID{9: 61}, "z"},
+		{"62", ID{9: 62}, "10"},
+		{"uint64-max", ID{2: 255, 255, 255, 255, 255, 255, 255, 255}, "LygHa16AHYF"},
+		{"uint64-max+1", ID{1: 1}, "LygHa16AHYG"},
+		{"max", MaxID(), "62iEp5bu9VZbsV"},
+		{"valid", ID{78, 111, 33, 96, 160, 255, 154, 10, 16, 51}, "1qny1P34ZqCF87"},
+	} {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			if actual, expected := c.in.Base62(), c.out; actual != expected {
+				t.Errorf("expected [%s], got [%s]", expected, actual)
+			}
+
+			// Leading zeroes do not change the value, so padded strings parse to the same ID.
+			for _, s := range []string{c.out, "000" + c.out} {
+				actual, err := FromBase62(s)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if expected := c.in; actual != expected {
+					t.Errorf("expected [%v], got [%v]", expected, actual)
+				}
+			}
+		})
+	}
+
+	// Round-trips regardless of the length of the string.
+	for i := 0; i < 1000; i++ {
+		id := New(byte(i))
+
+		if actual, err := FromBase62(id.Base62()); err != nil || actual != id {
+			t.Errorf("expected [%v], got [%v] (%v)", id, actual, err)
+		}
+	}
+}
+
+func TestID_DecimalPadded_SortOrder(t *testing.T) {
+	var (
+		tn  = time.Now()
+		ids = []ID{
+			{9: 9},
+			{9: 10},
+			{1: 1},
+			NewWithTime(0, tn),
+			NewWithTime(255, tn),
+			NewWithTime(0, tn.Add(time.Hour)),
+			MaxID(),
+		}
+	)
+
+	for i := 1; i < len(ids); i++ {
+		a, b := ids[i-1].DecimalPadded(), ids[i].DecimalPadded()
+
+		if actual, expected := strings.Compare(a, b), ids[i-1].Compare(ids[i]); actual != expected {
+			t.Errorf("%d: expected [%d], got [%d] comparing [%s] and [%s]", i, expected, actual, a, b)
+		}
+	}
+}
+
+func TestKeyOf(t *testing.T) {
+	var (
+		tn  = time.Now()
+		ids = []ID{
+			NewWithTime(0, tn.Add(time.Hour)),
+			NewWithTime(255, tn),
+			NewWithTime(0, tn),
+			MaxID(),
+			{},
+		}
+		tree = make(map[Key]ID, len(ids))
+		keys = make([]Key, 0, len(ids))
+	)
+
+	// A minimal sorted map - keys kept in order alongside a map of the values.
+	for _, id := range ids {
+		k := KeyOf(id)
+		if actual, expected := ID(k), id; actual != expected {
+			t.Errorf("expected [%v], got [%v]", expected, actual)
+		}
+
+		tree[k] = id
+
+		i := sort.Search(len(keys), func(i int) bool { return bytes.Compare(keys[i][:], k[:]) >= 0 })
+		keys = append(keys, Key{})
+		copy(keys[i+1:], keys[i:])
+		keys[i] = k
+	}
+
+	sorted := make([]ID, len(ids))
+	copy(sorted, ids)
+	Sort(sorted)
+
+	for i, k := range keys {
+		if actual, expected := tree[k], sorted[i]; actual != expected {
+			t.Errorf("%d: expected [%v], got [%v]", i, expected, actual)
+		}
+	}
+}
+
+func TestID_Gob_RoundTrip(t *testing.T) {
+	type record struct {
+		ID    ID
+		Zero  ID
+		Label string
+	}
+
+	for _, id := range []ID{New(255), {}} {
+		var (
+			buf bytes.Buffer
+			src = record{ID: id, Label: "label"}
+		)
+
+		if err := gob.NewEncoder(&buf).Encode(src); err != nil {
+			t.Fatal(err)
+		}
+
+		var actual record
+		if err := gob.NewDecoder(&buf).Decode(&actual); err != nil {
+			t.Fatal(err)
+		}
+
+		if expected := src; actual != expected {
+			t.Errorf("expected [%v], got [%v]", expected, actual)
+		}
+	}
+}
+
+func TestID_Gob_Slice(t *testing.T) {
+	type batch struct {
+		IDs []ID
+	}
+
+	src := batch{IDs: []ID{New(255), {}, New(1)}}
+
+	var buf bytes.Buffer
+	enc := gob.NewEncoder(&buf)
+
+	// Both as a field and on its own.
+	if err := enc.Encode(src); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := enc.Encode(src.IDs); err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		dec    = gob.NewDecoder(&buf)
+		actual batch
+		ids    []ID
+	)
+
+	if err := dec.Decode(&actual); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := dec.Decode(&ids); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(actual, src) {
+		t.Errorf("expected [%v], got [%v]", src, actual)
+	}
+
+	if !reflect.DeepEqual(ids, src.IDs) {
+		t.Errorf("expected [%v], got [%v]", src.IDs, ids)
+	}
+}
+
+func TestID_GobDecode_InvalidSize(t *testing.T) {
+	var id ID
+
+	err := id.GobDecode([]byte{1, 2, 3})
+	if actual, expected := reflect.TypeOf(err), reflect.TypeOf(&InvalidDataSizeError{}); actual != expected {
+		t.Errorf("expected error type [%s], got [%s]", expected, actual)
+	}
+}
+
+func TestID_LittleEndian(t *testing.T) {
+	id := ID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+
+	if actual, expected := id.LittleEndian(), [SizeBinary]byte{5, 4, 3, 2, 1, 10, 9, 8, 7, 6}; actual != expected {
+		t.Errorf("expected [%v], got [%v]", expected, actual)
+	}
+
+	// The timestamp block of the little-endian form reads back as the timestamp block of the ID.
+	var (
+		src = New(255)
+		le  = src.LittleEndian()
+		buf [8]byte
+	)
+
+	copy(buf[:5], le[:5])
+	if actual, expected := binary.LittleEndian.Uint64(buf[:]), binary.BigEndian.Uint64(src[:])>>24; actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+}
+
+func TestID_LittleEndian_RoundTrip(t *testing.T) {
+	for _, id := range []ID{{}, New(255), New(0), {255, 255, 255, 255, 255, 255, 255, 255, 255, 255}} {
+		if actual, expected := FromLittleEndian(id.LittleEndian()), id; actual != expected {
+			t.Errorf("expected [%v], got [%v]", expected, actual)
+		}
+	}
+}
+
+func TestID_StringUpper(t *testing.T) {
+	id := ID{78, 111, 33, 96, 160, 255, 154, 10, 16, 51}
+
+	if actual, expected := id.StringUpper(), "BRPK4Q72XWF2M63L"; actual != expected {
+		t.Errorf("expected [%s], got [%s]", expected, actual)
+	}
+
+	// Upper-cased strings retain the sort order of IDs.
+	for i := 0; i < 256; i++ {
+		a, b := New(byte(i)), New(byte(i))
+		if a.StringUpper() >= b.StringUpper() {
+			t.Errorf("expected [%s] to sort before [%s]", a.StringUpper(), b.StringUpper())
+		}
+
+		if actual, err := FromEncodedStringInsensitive(a.StringUpper()); err != nil || actual != a {
+			t.Errorf("expected [%v], got [%v] (%v)", a, actual, err)
+		}
+	}
+}
+
+func TestID_Pseudonym(t *testing.T) {
+	var (
+		id    = ID{78, 111, 33, 96, 160, 255, 154, 10, 16, 51}
+		other = ID{78, 111, 33, 96, 160, 255, 154, 10, 16, 52}
+		salt  = []byte("pepper")
+	)
+
+	p := id.Pseudonym(salt)
+
+	// Pinned, as pseudonyms must remain stable across versions to stay correlatable.
+	if expected := "ssnboew7vuthqxjad7dlnb4j9wrv7f4n8naibkgf2xpngtrf2xqi"; p != expected {
+		t.Errorf("expected [%s], got [%s]", expected, p)
+	}
+
+	if actual, expected := id.Pseudonym([]byte("pepper")), p; actual != expected {
+		t.Errorf("expected [%s], got [%s]", expected, actual)
+	}
+
+	if actual := id.Pseudonym([]byte("salt")); actual == p {
+		t.Errorf("expected pseudonyms under different salts to differ, got [%s] for both", actual)
+	}
+
+	if actual := other.Pseudonym(salt); actual == p {
+		t.Errorf("expected pseudonyms of different IDs to differ, got [%s] for both", actual)
+	}
+}
@@ -0,0 +1,20 @@
+package internal
+
+import _ "unsafe" // Required for go:linkname
+
+//go:linkname procPin runtime.procPin
+func procPin() int
+
+//go:linkname procUnpin runtime.procUnpin
+func procUnpin()
+
+// ProcID returns the ID of the P (in the sense of the runtime's scheduler) the calling goroutine is
+// running on, in range [0, GOMAXPROCS). The goroutine is not kept pinned to it - it may be running
+// on another P by the time the ID gets used - so it is only suitable as a hint, e.g. to spread callers
+// across resources to reduce contention.
+func ProcID() int {
+	id := procPin()
+	procUnpin()
+
+	return id
+}
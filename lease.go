@@ -0,0 +1,168 @@
+package sno
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// SequenceLeaser hands out bands of sequences (leases) to Generators, letting multiple Generators -
+// typically in separate processes - share a single Partition without collisions, as long as each of them
+// generates within bands disjoint from those leased to the others.
+//
+// See GeneratorOptions.Leaser.
+type SequenceLeaser interface {
+	// Lease returns the bounds (inclusive) of a band of sequences for the calling Generator to use
+	// exclusively. The band must lie within the sequence bounds of the Generator.
+	//
+	// A Generator keeps using its most recent band in each timeframe, until it exhausts it within
+	// a single timeframe, at which point it leases a new one. Bands superseded that way must not be
+	// leased again to other Generators sharing the Partition until the timeframe they got superseded
+	// in has passed.
+	Lease() (min, max uint16, err error)
+}
+
+// acquireLeased is the counterpart of acquire for Generators which draw sequences from leases.
+//
+// Unlike acquire, it is guarded by a lock as a lease round-trip needs to happen while no other
+// caller proceeds within the exhausted band.
+func (g *Generator) acquireLeased(ctx context.Context, wait bool) (units uint64, tick uint32, seq uint32, ok bool) {
+	g.leaseMu.Lock()
+
+retry:
+	var (
+		wallHi  = atomic.LoadUint64(&g.wallHi)
+		wallNow = g.now()
+	)
+
+	if g.logical && wallNow < wallHi {
+		wallNow = wallHi
+	}
+
+	switch {
+	case wallNow == wallHi:
+		if seq = atomic.LoadUint32(&g.seq) + 1; seq <= g.leaseMax {
+			break
+		}
+
+		// The band got exhausted within the current timeframe. After a failed attempt no further
+		// attempts happen within the same timeframe, so that a failing store does not get hammered by retries.
+		if g.leaseWall != wallHi {
+			if min, max, err := g.leaser.Lease(); err == nil && g.validLease(min, max) {
+				g.leaseMin, g.leaseMax = uint32(min), uint32(max)
+				seq = g.leaseMin
+
+				break
+			}
+
+			g.leaseWall = wallHi
+		}
+
+		// Without a new lease, we fall back to waiting for the next timeframe to reuse the band we've got.
+		if g.logical {
+			if g.floorSave != nil {
+				g.saveFloor(wallHi + 1)
+			}
+
+			g.progressLeased(wallHi, wallHi+1)
+			seq = g.leaseMin
+
+			break
+		}
+
+		if !wait {
+			g.leaseMu.Unlock()
+
+			return wallHi, 0, seq, false
+		}
+
+		g.leaseMu.Unlock()
+
+		if ctx != nil {
+			t := time.NewTimer(TimeUnit / 4)
+
+			select {
+			case <-ctx.Done():
+				t.Stop()
+				return wallHi, 0, seq, false
+			case <-t.C:
+			}
+		} else {
+			time.Sleep(TimeUnit / 4)
+		}
+
+		g.leaseMu.Lock()
+
+		goto retry
+
+	case wallNow > wallHi:
+		if g.floorSave != nil {
+			g.saveFloor(wallNow)
+		}
+
+		g.progressLeased(wallHi, wallNow)
+		seq = g.leaseMin
+
+	default:
+		// Regressions get handled the same way New handles them - except we're already exclusive.
+		if wallNow <= g.wallSafe {
+			g.leaseMu.Unlock()
+			g.regressionSleep(time.Duration(g.wallSafe - wallNow))
+			g.leaseMu.Lock()
+
+			goto retry
+		}
+
+		atomic.StoreUint64(&g.wallSafe, wallHi)
+		atomic.AddUint32(&g.drifts, 1)
+		g.progressLeased(wallHi, wallNow)
+		seq = g.leaseMin
+	}
+
+	atomic.StoreUint32(&g.seq, seq)
+	g.leaseLen++
+
+	units, tick = atomic.LoadUint64(&g.wallHi), atomic.LoadUint32(&g.drifts)&1
+
+	g.leaseMu.Unlock()
+
+	return units, tick, seq, true
+}
+
+// progressLeased moves a leased Generator from the timeframe of wallHi to the one of wallNow.
+// Must be called with leaseMu held.
+func (g *Generator) progressLeased(wallHi, wallNow uint64) {
+	if g.leaseLen > 0 {
+		g.recordFrameLen(wallHi, g.leaseLen)
+	}
+
+	atomic.StoreUint64(&g.wallHi, wallNow)
+	g.leaseLen = 0
+}
+
+// lease acquires the initial lease of a Generator.
+func (g *Generator) lease() error {
+	min, max, err := g.leaser.Lease()
+	if err != nil {
+		return err
+	}
+
+	if !g.validLease(min, max) {
+		return &InvalidSequenceBoundsError{
+			Cur: uint32(min),
+			Min: min,
+			Max: max,
+			Msg: errSequenceLeaseOutOfBoundsMsg,
+		}
+	}
+
+	g.leaseMin, g.leaseMax = uint32(min), uint32(max)
+	g.seq = g.leaseMin
+
+	return nil
+}
+
+// validLease checks whether the band of a lease lies within the sequence bounds of the Generator.
+func (g *Generator) validLease(min, max uint16) bool {
+	return min <= max && uint32(min) >= g.seqMin && uint32(max) <= g.seqMax
+}
@@ -0,0 +1,187 @@
+// +build test
+
+package sno
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/muyo/sno/internal"
+)
+
+// memLeaser is an in-memory SequenceLeaser handing out consecutive bands of a fixed size.
+type memLeaser struct {
+	mu    sync.Mutex
+	next  uint32
+	size  uint32
+	max   uint32
+	calls int
+}
+
+var errLeasesExhausted = errors.New("leases exhausted")
+
+func (l *memLeaser) Lease() (min, max uint16, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.calls++
+
+	if l.next+l.size-1 > l.max {
+		return 0, 0, errLeasesExhausted
+	}
+
+	min, max = uint16(l.next), uint16(l.next+l.size-1)
+	l.next += l.size
+
+	return
+}
+
+func TestGenerator_Leaser(t *testing.T) {
+	var (
+		leaser = &memLeaser{size: 8, max: MaxSequence}
+		gens   = make([]*Generator, 4)
+		seen   = make(map[ID]struct{})
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+	)
+
+	snotime = staticTime
+
+	for i := range gens {
+		g, err := NewGeneratorWithOptions(&GeneratorSnapshot{
+			Partition: Partition{'L', 'L'},
+		}, &GeneratorOptions{Leaser: leaser}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		gens[i] = g
+	}
+
+	// All generators share the partition and generate within the same timeframe, exhausting
+	// their bands several times over.
+	wg.Add(len(gens))
+
+	for _, g := range gens {
+		go func(g *Generator) {
+			defer wg.Done()
+
+			for i := 0; i < 50; i++ {
+				id := g.New(255)
+
+				mu.Lock()
+				if _, ok := seen[id]; ok {
+					t.Errorf("duplicate ID [%s]", id)
+				}
+				seen[id] = struct{}{}
+				mu.Unlock()
+			}
+		}(g)
+	}
+
+	wg.Wait()
+
+	// 4 initial leases plus 6 more per generator (50 IDs in bands of 8).
+	if actual, expected := leaser.calls, 4+4*6; actual != expected {
+		t.Errorf("expected [%d] lease calls, got [%d]", expected, actual)
+	}
+
+	// A new timeframe reuses the current band instead of leasing a new one.
+	atomic.AddUint64(staticWallNow, 1)
+
+	if gens[0].New(255); leaser.calls != 4+4*6 {
+		t.Errorf("expected no new lease on timeframe progression, got [%d] calls", leaser.calls)
+	}
+
+	snotime = internal.Snotime
+}
+
+func TestGenerator_Leaser_Fallback(t *testing.T) {
+	var (
+		leaser = &memLeaser{size: 8, max: 7}
+		wall   = internal.Snotime()
+	)
+
+	snotime = staticTime
+	atomic.StoreUint64(staticWallNow, wall)
+
+	g, err := NewGeneratorWithOptions(&GeneratorSnapshot{
+		LogicalClock: true,
+	}, &GeneratorOptions{Leaser: leaser}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The leaser can't hand out another band, so the logical clock advances instead and
+	// the band gets reused.
+	for i := 0; i < 16; i++ {
+		id := g.New(255)
+
+		if actual, expected := id.Sequence(), uint16(i%8); actual != expected {
+			t.Errorf("%d: expected [%d], got [%d]", i, expected, actual)
+		}
+
+		if actual, expected := id.Timestamp(), int64(wall+uint64(i/8))*TimeUnit+epochNsec; actual != expected {
+			t.Errorf("%d: expected [%d], got [%d]", i, expected, actual)
+		}
+	}
+
+	snotime = internal.Snotime
+}
+
+func TestGenerator_Leaser_RegressionSleeps(t *testing.T) {
+	clock := manualClock(1000)
+
+	g, err := NewGeneratorWithOptions(&GeneratorSnapshot{}, &GeneratorOptions{
+		Leaser: &memLeaser{size: 8, max: MaxSequence},
+		Clock:  clock.now,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g.New(255)
+
+	// The first regression tick-tocks, the second one (into the past before the first) must sleep.
+	clock.set(990)
+	g.New(255)
+	clock.set(985)
+
+	go func() {
+		time.Sleep(4 * TimeUnit)
+		clock.set(1001)
+	}()
+
+	g.New(255)
+
+	stats := g.Stats()
+	if stats.RegressionSleeps == 0 {
+		t.Error("expected at least [1] regression sleep recorded, got [0]")
+	}
+
+	if stats.RegressionSleepTime < TimeUnit {
+		t.Errorf("expected regression sleep time of at least [%d], got [%d]", time.Duration(TimeUnit), stats.RegressionSleepTime)
+	}
+}
+
+func TestGenerator_Leaser_Errors(t *testing.T) {
+	_, err := NewGeneratorWithOptions(&GeneratorSnapshot{}, &GeneratorOptions{Leaser: &memLeaser{size: 8, max: 0}}, nil)
+	if err != errLeasesExhausted {
+		t.Errorf("expected [%v], got [%v]", errLeasesExhausted, err)
+	}
+
+	_, err = NewGeneratorWithOptions(&GeneratorSnapshot{
+		SequenceMin: 16,
+		SequenceMax: 31,
+	}, &GeneratorOptions{Leaser: &memLeaser{size: 8, max: MaxSequence}}, nil)
+	if _, ok := err.(*InvalidSequenceBoundsError); !ok {
+		t.Fatalf("expected error type [%T], got [%T]", &InvalidSequenceBoundsError{}, err)
+	}
+
+	if actual, expected := err.(*InvalidSequenceBoundsError).Msg, errSequenceLeaseOutOfBoundsMsg; actual != expected {
+		t.Errorf("expected [%s], got [%s]", expected, actual)
+	}
+}
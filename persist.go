@@ -0,0 +1,196 @@
+package sno
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Persister stores snapshots of a Generator durably and loads them back, e.g. to restore the Generator
+// after a restart. See Generator.RunCheckpoints and FilePersister.
+type Persister interface {
+	// Store persists the given snapshot, replacing the one stored previously (if any).
+	Store(GeneratorSnapshot) error
+
+	// Load returns the snapshot stored most recently - or nil (and no error) if none has been stored yet,
+	// so that the result can be passed to NewGenerator as is.
+	Load() (*GeneratorSnapshot, error)
+}
+
+// FilePersister is a Persister which stores snapshots as JSON in the file at Path, replacing the file
+// atomically on each Store (see Generator.SaveFile).
+type FilePersister struct {
+	Path string
+}
+
+// Store implements Persister by writing the snapshot to the file atomically.
+func (p *FilePersister) Store(snapshot GeneratorSnapshot) error {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(p.Path, data)
+}
+
+// Load implements Persister by reading the snapshot from the file. Returns nil if the file does not exist.
+func (p *FilePersister) Load() (*GeneratorSnapshot, error) {
+	snapshot, err := loadSnapshotFile(p.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	return snapshot, err
+}
+
+// RunCheckpoints periodically persists Snapshots of the Generator using the given Persister, every given
+// interval, and persists a final one when the returned stop function gets called - see WithCheckpoints,
+// which it builds upon, for the semantics.
+//
+// Errors returned by the Persister get passed to the optional onError callback (e.g. to log them
+// or to alert on), which gets called synchronously from the checkpointing goroutine - or from stop,
+// for the final checkpoint. A failed checkpoint does not stop the ones that follow, as each
+// supersedes the previous.
+func (g *Generator) RunCheckpoints(p Persister, interval time.Duration, onError func(error)) (stop func()) {
+	return g.WithCheckpoints(interval, func(snapshot GeneratorSnapshot) {
+		if err := p.Store(snapshot); err != nil && onError != nil {
+			onError(err)
+		}
+	})
+}
+
+// SaveFile persists a Snapshot of the Generator as JSON to the file at the given path, to be restored
+// using LoadGeneratorFile - e.g. for simple deployments which have no database to store snapshots in.
+//
+// The file gets replaced atomically: the snapshot is written to a temporary file within the same
+// directory, synced to disk and only then renamed over the destination, so a crash mid-save leaves
+// either the previous or the new snapshot behind - never a torn one.
+//
+// Same as with Snapshot, the snapshot should be saved when the Generator is no longer in use,
+// as IDs generated after it got taken would not be accounted for upon restoring.
+func (g *Generator) SaveFile(path string) error {
+	return (&FilePersister{Path: path}).Store(g.Snapshot())
+}
+
+// LoadGeneratorFile returns a new Generator restored from the snapshot in the file at the given path,
+// as saved by Generator.SaveFile. See NewGenerator for the semantics of the channel.
+//
+// A file which can not be decoded results in an error, as does a missing file - callers which want
+// to fall back to defaults on the first start should check for the latter using os.IsNotExist.
+func LoadGeneratorFile(path string, c chan<- *SequenceOverflowNotification) (*Generator, error) {
+	snapshot, err := loadSnapshotFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewGenerator(snapshot, c)
+}
+
+// loadSnapshotFile reads a snapshot as JSON from the file at the given path.
+func loadSnapshotFile(path string) (*GeneratorSnapshot, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot GeneratorSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+
+	return &snapshot, nil
+}
+
+// WithCheckpoints periodically checkpoints the state of the Generator: it calls save with a fresh
+// Snapshot every given interval, on a background goroutine, e.g. for long-running producers persisting
+// their state on an interval (see SaveFile). The interval must be greater than zero.
+//
+// The returned stop function halts the checkpointing and then calls save once more with a final
+// Snapshot, before returning. Calls to save never overlap, and none happen after stop returns.
+// Stop is safe to call multiple times - only the first call has any effect.
+//
+// Snapshots taken while the Generator is in use may not account for IDs generated concurrently (see
+// Snapshot), so only the final one can be relied upon to be complete - provided the Generator is no
+// longer in use by the time stop gets called.
+func (g *Generator) WithCheckpoints(every time.Duration, save func(GeneratorSnapshot)) (stop func()) {
+	var (
+		ticker = time.NewTicker(every)
+		done   = make(chan struct{})
+		exited = make(chan struct{})
+		once   sync.Once
+	)
+
+	go func() {
+		defer close(exited)
+
+		for {
+			select {
+			case <-ticker.C:
+				save(g.Snapshot())
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		once.Do(func() {
+			ticker.Stop()
+			close(done)
+			<-exited
+
+			save(g.Snapshot())
+		})
+	}
+}
+
+// writeFileAtomic replaces the file at the given path with the given data, by writing it to a temporary
+// file within the same directory, syncing it and renaming it over the destination.
+func writeFileAtomic(path string, data []byte) (err error) {
+	dir, base := filepath.Split(path)
+	if dir == "" {
+		dir = "."
+	}
+
+	f, err := ioutil.TempFile(dir, base+".tmp")
+	if err != nil {
+		return err
+	}
+
+	// Past this point the temporary file must not be left behind on failures.
+	defer func() {
+		if err != nil {
+			_ = os.Remove(f.Name())
+		}
+	}()
+
+	if _, err = f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	if err = f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	if err = f.Close(); err != nil {
+		return err
+	}
+
+	if err = os.Rename(f.Name(), path); err != nil {
+		return err
+	}
+
+	// The rename itself is only durable once the directory entry is. Not all platforms
+	// support syncing directories, so this part is best-effort.
+	if d, derr := os.Open(dir); derr == nil {
+		_ = d.Sync()
+		_ = d.Close()
+	}
+
+	return nil
+}
@@ -0,0 +1,269 @@
+package sno
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGenerator_SaveFile_RoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sno")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	g, err := NewGenerator(&GeneratorSnapshot{
+		Name:        "persisted",
+		Partition:   Partition{'P', 'F'},
+		SequenceMin: 16,
+		SequenceMax: 1024,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var last ID
+	for i := 0; i < 64; i++ {
+		last = g.New(1)
+	}
+
+	path := filepath.Join(dir, "generator.json")
+
+	// Saving twice exercises replacing an existing file.
+	for i := 0; i < 2; i++ {
+		if err := g.SaveFile(path); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if actual, expected := len(files), 1; actual != expected {
+		t.Errorf("expected [%d] files to remain, got [%d]", expected, actual)
+	}
+
+	r, err := LoadGeneratorFile(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, actual := g.Snapshot(), r.Snapshot()
+	expected.Now, actual.Now = 0, 0
+
+	if actual.Name != expected.Name ||
+		actual.Partition != expected.Partition ||
+		actual.SequenceMin != expected.SequenceMin ||
+		actual.SequenceMax != expected.SequenceMax ||
+		actual.Sequence != expected.Sequence ||
+		actual.WallHi != expected.WallHi ||
+		actual.WallSafe != expected.WallSafe ||
+		actual.Drifts != expected.Drifts {
+		t.Errorf("expected [%+v], got [%+v]", expected, actual)
+	}
+
+	if id := r.New(1); id.Compare(last) <= 0 {
+		t.Errorf("expected [%s] to sort after [%s]", id, last)
+	}
+}
+
+func TestLoadGeneratorFile_Corrupted(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sno")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "generator.json")
+	if err := ioutil.WriteFile(path, []byte(`{"partition":[80,70],"sequ`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	g, err := LoadGeneratorFile(path, nil)
+	if g != nil {
+		t.Errorf("expected [nil], got [%v]", g)
+	}
+
+	if _, ok := err.(*json.SyntaxError); !ok {
+		t.Errorf("expected [%T], got [%T]", &json.SyntaxError{}, err)
+	}
+}
+
+func TestLoadGeneratorFile_Missing(t *testing.T) {
+	_, err := LoadGeneratorFile(filepath.Join(os.TempDir(), "sno-missing.json"), nil)
+	if !os.IsNotExist(err) {
+		t.Errorf("expected a not-exist error, got [%v]", err)
+	}
+}
+
+func TestGenerator_WithCheckpoints(t *testing.T) {
+	g, err := NewGenerator(&GeneratorSnapshot{
+		Partition: Partition{'C', 'P'},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		saves uint32
+		last  atomic.Value
+	)
+
+	start := time.Now()
+	stop := g.WithCheckpoints(20*time.Millisecond, func(s GeneratorSnapshot) {
+		atomic.AddUint32(&saves, 1)
+		last.Store(s)
+	})
+
+	time.Sleep(110 * time.Millisecond)
+
+	// The cadence is subject to scheduling, so only bound it from both sides.
+	n := atomic.LoadUint32(&saves)
+	if max := uint32(time.Since(start) / (20 * time.Millisecond)); n < 2 || n > max {
+		t.Errorf("expected within [2, %d] saves, got [%d]", max, n)
+	}
+
+	g.New(255)
+	stop()
+
+	if actual := atomic.LoadUint32(&saves); actual <= n {
+		t.Errorf("expected a final save on stop, got [%d] saves before and [%d] after", n, actual)
+	}
+
+	n = atomic.LoadUint32(&saves)
+
+	// The final snapshot accounts for everything generated before stop.
+	if actual, expected := last.Load().(GeneratorSnapshot).WallHi, g.Snapshot().WallHi; actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	// Neither the ticker nor further calls to stop result in saves past stop.
+	stop()
+	time.Sleep(50 * time.Millisecond)
+
+	if actual, expected := atomic.LoadUint32(&saves), n; actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+}
+
+func TestGenerator_RunCheckpoints_Restart(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sno")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	p := &FilePersister{Path: filepath.Join(dir, "generator.json")}
+
+	// Nothing stored yet.
+	if snapshot, err := p.Load(); snapshot != nil || err != nil {
+		t.Fatalf("expected [nil, nil], got [%v, %v]", snapshot, err)
+	}
+
+	clock := manualClock(1000)
+
+	g, err := NewGeneratorWithOptions(&GeneratorSnapshot{
+		Partition:   Partition{'R', 'S'},
+		SequenceMin: 1024,
+		SequenceMax: 2047,
+	}, &GeneratorOptions{Clock: clock.now}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stop := g.RunCheckpoints(p, 5*time.Millisecond, func(err error) { t.Error(err) })
+
+	// Regress the clock, so that WallSafe gets set as well.
+	g.New(255)
+	clock.set(990)
+
+	for i := 0; i < 10; i++ {
+		g.New(255)
+	}
+
+	stop()
+
+	// Simulate a restart.
+	snapshot, err := p.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewGeneratorWithOptions(snapshot, &GeneratorOptions{Clock: clock.now}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, actual := g.Snapshot(), r.Snapshot()
+
+	if actual.WallHi != 990 || actual.WallHi != expected.WallHi {
+		t.Errorf("expected WallHi [%d], got [%d]", expected.WallHi, actual.WallHi)
+	}
+
+	if actual.WallSafe != 1000 || actual.WallSafe != expected.WallSafe {
+		t.Errorf("expected WallSafe [%d], got [%d]", expected.WallSafe, actual.WallSafe)
+	}
+
+	if actual.Sequence != 1033 || actual.Sequence != expected.Sequence {
+		t.Errorf("expected Sequence [%d], got [%d]", expected.Sequence, actual.Sequence)
+	}
+
+	// The restored Generator carries on where the original one left off.
+	if actual, expected := r.New(255).Sequence(), uint16(1034); actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+}
+
+// failingPersister is a Persister whose stores always fail.
+type failingPersister struct {
+	err error
+}
+
+func (p *failingPersister) Store(GeneratorSnapshot) error     { return p.err }
+func (p *failingPersister) Load() (*GeneratorSnapshot, error) { return nil, p.err }
+
+func TestGenerator_RunCheckpoints_Errors(t *testing.T) {
+	g, err := NewGenerator(&GeneratorSnapshot{
+		Partition: Partition{'R', 'E'},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		p    = &failingPersister{err: errors.New("store failed")}
+		errs uint32
+	)
+
+	stop := g.RunCheckpoints(p, 5*time.Millisecond, func(err error) {
+		if err != p.err {
+			t.Errorf("expected [%v], got [%v]", p.err, err)
+		}
+
+		atomic.AddUint32(&errs, 1)
+	})
+
+	time.Sleep(30 * time.Millisecond)
+	n := atomic.LoadUint32(&errs)
+	stop()
+
+	// Each failed checkpoint gets reported, the final one included - without halting the ones that follow.
+	if n < 2 {
+		t.Errorf("expected at least [2] errors before stop, got [%d]", n)
+	}
+
+	if actual, expected := atomic.LoadUint32(&errs), n+1; actual < expected {
+		t.Errorf("expected at least [%d] errors after stop, got [%d]", expected, actual)
+	}
+
+	// A nil onError drops the errors.
+	g.RunCheckpoints(p, 5*time.Millisecond, nil)()
+}
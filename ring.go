@@ -0,0 +1,83 @@
+package sno
+
+import "sync"
+
+// Ring holds the most recent IDs pushed onto it, up to its fixed capacity - once full, each push
+// overwrites the oldest ID held. It is meant as a bounded, fixed-memory cache of recently generated IDs.
+// See Generator.NewInto.
+//
+// A Ring must be constructed using NewRing. It is safe for concurrent use, but IDs generated
+// concurrently may get pushed in a different order than they got generated in.
+type Ring struct {
+	mu   sync.Mutex
+	ids  []ID
+	next int // Index the next push writes to.
+	len  int
+}
+
+// NewRing returns a new Ring with a capacity of size IDs. It panics with an InvalidSizeError if size <= 0.
+func NewRing(size int) *Ring {
+	if size <= 0 {
+		panic(&InvalidSizeError{Func: "NewRing", Size: size})
+	}
+
+	return &Ring{
+		ids: make([]ID, size),
+	}
+}
+
+// Push adds the given ID to the Ring, overwriting the oldest ID held if the Ring is full.
+func (r *Ring) Push(id ID) {
+	r.mu.Lock()
+	r.ids[r.next] = id
+
+	if r.next++; r.next == len(r.ids) {
+		r.next = 0
+	}
+
+	if r.len < len(r.ids) {
+		r.len++
+	}
+	r.mu.Unlock()
+}
+
+// Len returns the number of IDs currently held by the Ring.
+func (r *Ring) Len() int {
+	r.mu.Lock()
+	n := r.len
+	r.mu.Unlock()
+
+	return n
+}
+
+// Cap returns the capacity of the Ring.
+func (r *Ring) Cap() int {
+	return len(r.ids)
+}
+
+// IDs returns a copy of the IDs currently held by the Ring, oldest first.
+func (r *Ring) IDs() []ID {
+	r.mu.Lock()
+	out := make([]ID, r.len)
+
+	// While the Ring is not full yet, its IDs start at index 0 - afterwards at the index
+	// of the next push.
+	start := 0
+	if r.len == len(r.ids) {
+		start = r.next
+	}
+
+	n := copy(out, r.ids[start:r.len])
+	copy(out[n:], r.ids[:start])
+	r.mu.Unlock()
+
+	return out
+}
+
+// NewInto generates a new ID like New and pushes it onto the given Ring before returning it.
+func (g *Generator) NewInto(r *Ring, meta byte) ID {
+	id := g.New(meta)
+	r.Push(id)
+
+	return id
+}
@@ -0,0 +1,61 @@
+package sno
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRing_WrapAround(t *testing.T) {
+	g, err := NewGenerator(&GeneratorSnapshot{
+		Partition: Partition{'R', 'G'},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		r   = NewRing(4)
+		ids = make([]ID, 10)
+	)
+
+	if actual, expected := r.IDs(), []ID{}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected [%v], got [%v]", expected, actual)
+	}
+
+	for i := range ids {
+		ids[i] = g.NewInto(r, 255)
+
+		// Until the ring is full, it holds everything generated so far - afterwards only the most recent.
+		from := 0
+		if i >= r.Cap() {
+			from = i + 1 - r.Cap()
+		}
+
+		if actual, expected := r.IDs(), ids[from:i+1]; !reflect.DeepEqual(actual, expected) {
+			t.Errorf("%d: expected [%v], got [%v]", i, expected, actual)
+		}
+
+		if actual, expected := r.Len(), i+1-from; actual != expected {
+			t.Errorf("%d: expected [%d], got [%d]", i, expected, actual)
+		}
+	}
+
+	if actual, expected := r.Cap(), 4; actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+}
+
+func TestRing_InvalidSize(t *testing.T) {
+	defer func() {
+		err, ok := recover().(*InvalidSizeError)
+		if !ok {
+			t.Fatalf("expected a panic with [%T]", &InvalidSizeError{})
+		}
+
+		if actual, expected := err.Func, "NewRing"; actual != expected {
+			t.Errorf("expected [%s], got [%s]", expected, actual)
+		}
+	}()
+
+	NewRing(0)
+}
@@ -0,0 +1,151 @@
+package sno
+
+import (
+	"runtime"
+
+	"github.com/muyo/sno/internal"
+)
+
+// ShardedGenerator fans out generation across several Generators (shards) for the same partition,
+// each owning a disjoint slice of the sequence pool, e.g. for producers generating IDs from many goroutines
+// in parallel - where a single Generator's sequence and time bookkeeping become a point of contention.
+//
+// Calls to New get dispatched to a shard based on the P (see runtime.GOMAXPROCS) the calling goroutine
+// is running on, so that goroutines running in parallel tend to hit different shards. As each shard only
+// owns a slice of the sequence pool, calls spill over into the other shards when the pool of their shard
+// is exhausted - and only wait for the next timeframe once all of them are.
+//
+// IDs are unique across all shards, as their sequences can not collide. They are ordered the same way
+// as IDs of a single Generator are - but only among the IDs of each shard. IDs of different shards
+// within the same timeframe are not ordered by the time they got generated.
+type ShardedGenerator struct {
+	shards []*Generator
+}
+
+// NewShardedGenerator returns a new ShardedGenerator with n shards, or runtime.GOMAXPROCS(0) shards
+// if n <= 0. See NewGeneratorWithOptions for the semantics of the optional snapshot and options, and of
+// the channel - the options and the channel get shared by all shards.
+//
+// Each shard gets created from a copy of the snapshot, with the sequence pool of the snapshot split
+// evenly across them (the last shard getting the remainder), so the options of the snapshot (e.g. Jitter)
+// apply to each shard on its own. The partition gets acquired once for all shards if the snapshot is nil.
+//
+// Returns an InvalidSequenceBoundsError if the sequence pool is too small to give each shard a pool
+// of a valid capacity and a ShardedOptionError if given a Leaser, LoadFloor, SaveFloor or MinFirstID -
+// all of which assume a single Generator - along with any other error NewGeneratorWithOptions would
+// return for the shards.
+func NewShardedGenerator(snapshot *GeneratorSnapshot, opts *GeneratorOptions, n int, c chan<- *SequenceOverflowNotification) (*ShardedGenerator, error) {
+	var o GeneratorOptions
+	if opts != nil {
+		o = *opts
+	}
+
+	switch {
+	case o.Leaser != nil:
+		return nil, &ShardedOptionError{Option: "GeneratorOptions.Leaser"}
+	case o.LoadFloor != nil:
+		return nil, &ShardedOptionError{Option: "GeneratorOptions.LoadFloor"}
+	case o.SaveFloor != nil:
+		return nil, &ShardedOptionError{Option: "GeneratorOptions.SaveFloor"}
+	case snapshot != nil && snapshot.MinFirstID != nil:
+		return nil, &ShardedOptionError{Option: "GeneratorSnapshot.MinFirstID"}
+	}
+
+	var s GeneratorSnapshot
+	if snapshot != nil {
+		s = *snapshot
+	} else {
+		partition, err := genPartition()
+		if err != nil {
+			return nil, err
+		}
+
+		s.Partition = partitionToPublicRepr(partition)
+	}
+
+	if err := sanitizeSnapshotBounds(&s); err != nil {
+		return nil, err
+	}
+
+	if n <= 0 {
+		n = runtime.GOMAXPROCS(0)
+	}
+
+	var (
+		sg   = &ShardedGenerator{shards: make([]*Generator, n)}
+		pool = uint32(s.SequenceMax-s.SequenceMin) + 1
+		size = pool / uint32(n)
+	)
+
+	if size < minSequencePoolSize {
+		return nil, invalidSequenceBounds(&s, errSequencePoolTooSmallMsg)
+	}
+
+	for i := range sg.shards {
+		shard := s
+		shard.SequenceMin = s.SequenceMin + uint16(uint32(i)*size)
+		shard.SequenceMax = shard.SequenceMin + uint16(size-1)
+
+		if i == n-1 {
+			shard.SequenceMax = s.SequenceMax
+		}
+
+		// The sequence of a restored snapshot carries over into the shards, so that those whose pools
+		// have already been drawn from within the snapshot's timeframe do not draw from them again.
+		shard.Sequence = s.Sequence
+		if min := uint32(shard.SequenceMin); shard.Sequence < min {
+			shard.Sequence = min
+		} else if max := uint32(shard.SequenceMax) + 1; shard.Sequence > max {
+			shard.Sequence = max
+		}
+
+		g, err := newGeneratorFromSnapshot(shard, o, c)
+		if err != nil {
+			return nil, err
+		}
+
+		sg.shards[i] = g
+	}
+
+	return sg, nil
+}
+
+// New generates a new ID using the current system time for its timestamp, like Generator.New,
+// using the shard assigned to the P the calling goroutine is running on (or the next one which
+// is not exhausted).
+//
+// Panics with a ReservedMetaError if the given metabyte is reserved (see GeneratorSnapshot.ReservedMeta).
+func (sg *ShardedGenerator) New(meta byte) (id ID) {
+	var (
+		n = len(sg.shards)
+		i = internal.ProcID() % n
+	)
+
+	// All shards share the same set of reserved metabytes.
+	sg.shards[i].checkMeta(meta)
+
+	for k := 0; k < n; k++ {
+		g := sg.shards[(i+k)%n]
+
+		units, tick, seq, ok := g.acquire(nil, 1, false)
+		if !ok {
+			continue
+		}
+
+		g.applyTimestamp(&id, units, tick)
+		g.applyPayload(&id, meta, seq)
+
+		return id
+	}
+
+	return sg.shards[i].New(meta)
+}
+
+// Shards returns the shards of the ShardedGenerator, e.g. to take their Snapshots or Stats.
+// The returned slice is a copy, but the Generators are not.
+func (sg *ShardedGenerator) Shards() []*Generator {
+	shards := make([]*Generator, len(sg.shards))
+	copy(shards, sg.shards)
+
+	return shards
+}
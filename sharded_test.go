@@ -0,0 +1,174 @@
+package sno
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestNewShardedGenerator_Bounds(t *testing.T) {
+	sg, err := NewShardedGenerator(&GeneratorSnapshot{
+		Partition:   Partition{'S', 'H'},
+		SequenceMin: 1000,
+		SequenceMax: 1099,
+	}, nil, 3, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	shards := sg.Shards()
+	if actual, expected := len(shards), 3; actual != expected {
+		t.Fatalf("expected [%d], got [%d]", expected, actual)
+	}
+
+	for i, c := range []struct {
+		min, max uint16
+	}{
+		{1000, 1032},
+		{1033, 1065},
+		{1066, 1099}, // The remainder goes to the last shard.
+	} {
+		s := shards[i].Snapshot()
+
+		if s.SequenceMin != c.min || s.SequenceMax != c.max {
+			t.Errorf("%d: expected [%d, %d], got [%d, %d]", i, c.min, c.max, s.SequenceMin, s.SequenceMax)
+		}
+
+		if actual, expected := s.Partition, (Partition{'S', 'H'}); actual != expected {
+			t.Errorf("%d: expected [%v], got [%v]", i, expected, actual)
+		}
+	}
+
+	if _, err := NewShardedGenerator(&GeneratorSnapshot{
+		SequenceMin: 1000,
+		SequenceMax: 1099,
+	}, nil, 50, nil); err == nil {
+		t.Errorf("expected error, got none")
+	} else if _, ok := err.(*InvalidSequenceBoundsError); !ok {
+		t.Errorf("expected [%T], got [%T]", &InvalidSequenceBoundsError{}, err)
+	}
+
+	for _, c := range []struct {
+		snapshot *GeneratorSnapshot
+		opts     *GeneratorOptions
+		option   string
+	}{
+		{&GeneratorSnapshot{}, &GeneratorOptions{Leaser: &staticLeaser{}}, "GeneratorOptions.Leaser"},
+		{&GeneratorSnapshot{}, &GeneratorOptions{LoadFloor: func() uint64 { return 0 }}, "GeneratorOptions.LoadFloor"},
+		{&GeneratorSnapshot{}, &GeneratorOptions{SaveFloor: func(uint64) {}}, "GeneratorOptions.SaveFloor"},
+		{&GeneratorSnapshot{MinFirstID: &ID{}}, nil, "GeneratorSnapshot.MinFirstID"},
+	} {
+		_, err := NewShardedGenerator(c.snapshot, c.opts, 2, nil)
+		if actual, expected := err, (&ShardedOptionError{Option: c.option}); !reflect.DeepEqual(actual, expected) {
+			t.Errorf("expected [%v], got [%v]", expected, actual)
+		}
+	}
+}
+
+// staticLeaser is a SequenceLeaser which always hands out the entire sequence pool.
+type staticLeaser struct{}
+
+func (staticLeaser) Lease() (min, max uint16, err error) { return 0, MaxSequence, nil }
+
+func TestNewShardedGenerator_RestoredSequence(t *testing.T) {
+	clock := manualClock(1000)
+
+	sg, err := NewShardedGenerator(&GeneratorSnapshot{
+		Partition:   Partition{'S', 'H'},
+		SequenceMin: 0,
+		SequenceMax: 29,
+		Sequence:    15,
+		WallHi:      1000,
+	}, &GeneratorOptions{Clock: clock.now}, 3, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Within the snapshot's timeframe, shards do not draw from sequences which were already drawn.
+	for i, expected := range []uint32{10, 15, 20} {
+		if actual := sg.Shards()[i].Snapshot().Sequence; actual != expected {
+			t.Errorf("%d: expected [%d], got [%d]", i, expected, actual)
+		}
+	}
+}
+
+func TestShardedGenerator_New_Unique(t *testing.T) {
+	sg, err := NewShardedGenerator(&GeneratorSnapshot{
+		Partition: Partition{'S', 'H'},
+	}, nil, 4, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const (
+		workers = 8
+		perN    = 10000
+	)
+
+	var (
+		wg  sync.WaitGroup
+		ids = make([][]ID, workers)
+	)
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+
+			ids[w] = make([]ID, perN)
+			for i := range ids[w] {
+				ids[w][i] = sg.New(255)
+			}
+		}(w)
+	}
+
+	wg.Wait()
+
+	seen := make(map[ID]struct{}, workers*perN)
+	for w := range ids {
+		for _, id := range ids[w] {
+			if _, ok := seen[id]; ok {
+				t.Fatalf("duplicate ID [%s]", id)
+			}
+
+			seen[id] = struct{}{}
+		}
+	}
+}
+
+func TestShardedGenerator_New_SpillOver(t *testing.T) {
+	clock := manualClock(1000)
+
+	sg, err := NewShardedGenerator(&GeneratorSnapshot{
+		Partition:   Partition{'S', 'H'},
+		SequenceMin: 0,
+		SequenceMax: 23,
+	}, &GeneratorOptions{Clock: clock.now}, 2, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Regardless of the shard a call gets dispatched to, the pools of both get drawn from
+	// before any call overflows.
+	seen := make(map[uint16]struct{})
+	for i := 0; i < 24; i++ {
+		id := sg.New(255)
+		if id.IsZero() {
+			t.Fatalf("%d: expected an ID, got the zero ID", i)
+		}
+
+		seen[id.Sequence()] = struct{}{}
+	}
+
+	if actual, expected := len(seen), 24; actual != expected {
+		t.Errorf("expected [%d] distinct sequences, got [%d]", expected, actual)
+	}
+
+	for i, g := range sg.Shards() {
+		if _, err := g.TryNew(255); err == nil {
+			t.Errorf("%d: expected error, got none", i)
+		} else if _, ok := err.(*SequencePoolExhaustedError); !ok {
+			t.Errorf("%d: expected [%T], got [%T]", i, &SequencePoolExhaustedError{}, err)
+		}
+	}
+}
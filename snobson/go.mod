@@ -0,0 +1,10 @@
+module github.com/muyo/sno/snobson
+
+go 1.18
+
+require (
+	github.com/muyo/sno v1.2.0
+	go.mongodb.org/mongo-driver v1.17.6
+)
+
+replace github.com/muyo/sno => ../
@@ -0,0 +1,127 @@
+// Package snobson provides a BSON codec for sno IDs, for use with the MongoDB driver - e.g. to store IDs
+// in _id fields. Without it, the driver encodes IDs as arrays of 10 integers.
+//
+// The codec needs to be registered with the registry the driver encodes and decodes with:
+//
+//	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri).SetRegistry(snobson.NewRegistry()))
+//
+// It lives in a module of its own, so that sno itself does not depend on the driver.
+package snobson
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/muyo/sno"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// Representation is the BSON type IDs get encoded as.
+type Representation int
+
+const (
+	// String encodes IDs as their canonical 16-character string, which sorts like the IDs do and is
+	// readable in the shell and in indexes.
+	String Representation = iota
+
+	// Binary encodes IDs as their 10 bytes, in a BSON binary of the generic subtype (0x00).
+	Binary
+)
+
+// DefaultRepresentation is the Representation used by Register and NewRegistry. It is meant to be set
+// once, before registering the codec - registered codecs do not pick up later changes.
+var DefaultRepresentation = String
+
+var tID = reflect.TypeOf(sno.ID{})
+
+// Codec is a bsoncodec.ValueEncoder and bsoncodec.ValueDecoder for sno IDs.
+//
+// Zero IDs get encoded as BSON null, the same way ID.MarshalJSON encodes them as JSON null, and null
+// decodes to the zero ID. Regardless of the Representation, both strings and binaries get decoded,
+// so that the Representation can be changed without migrating existing documents.
+type Codec struct {
+	Representation Representation
+}
+
+// EncodeValue implements bsoncodec.ValueEncoder.
+func (c *Codec) EncodeValue(_ bsoncodec.EncodeContext, vw bsonrw.ValueWriter, val reflect.Value) error {
+	if !val.IsValid() || val.Type() != tID {
+		return bsoncodec.ValueEncoderError{Name: "snobson.EncodeValue", Types: []reflect.Type{tID}, Received: val}
+	}
+
+	id := val.Interface().(sno.ID)
+	if id.IsZero() {
+		return vw.WriteNull()
+	}
+
+	if c.Representation == Binary {
+		return vw.WriteBinaryWithSubtype(id[:], bson.TypeBinaryGeneric)
+	}
+
+	return vw.WriteString(id.String())
+}
+
+// DecodeValue implements bsoncodec.ValueDecoder.
+func (c *Codec) DecodeValue(_ bsoncodec.DecodeContext, vr bsonrw.ValueReader, val reflect.Value) error {
+	if !val.CanSet() || val.Type() != tID {
+		return bsoncodec.ValueDecoderError{Name: "snobson.DecodeValue", Types: []reflect.Type{tID}, Received: val}
+	}
+
+	var (
+		id  sno.ID
+		err error
+	)
+
+	switch t := vr.Type(); t {
+	case bsontype.Null:
+		err = vr.ReadNull()
+	case bsontype.String:
+		var s string
+		if s, err = vr.ReadString(); err == nil {
+			id, err = sno.FromEncodedStringStrict(s)
+		}
+	case bsontype.Binary:
+		var (
+			b       []byte
+			subtype byte
+		)
+
+		if b, subtype, err = vr.ReadBinary(); err == nil {
+			if subtype != bson.TypeBinaryGeneric {
+				return fmt.Errorf("snobson: cannot decode binary subtype %#x into a sno.ID", subtype)
+			}
+
+			id, err = sno.FromBinaryBytes(b)
+		}
+	default:
+		return fmt.Errorf("snobson: cannot decode %v into a sno.ID", t)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	val.Set(reflect.ValueOf(id))
+
+	return nil
+}
+
+// Register registers a Codec using DefaultRepresentation for sno IDs with the given registry.
+func Register(reg *bsoncodec.Registry) {
+	c := &Codec{Representation: DefaultRepresentation}
+
+	reg.RegisterTypeEncoder(tID, c)
+	reg.RegisterTypeDecoder(tID, c)
+}
+
+// NewRegistry returns a new registry with the default encoders and decoders of the driver
+// (see bson.NewRegistry) and a Codec using DefaultRepresentation for sno IDs.
+func NewRegistry() *bsoncodec.Registry {
+	reg := bson.NewRegistry()
+	Register(reg)
+
+	return reg
+}
@@ -0,0 +1,191 @@
+package snobson
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/muyo/sno"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type record struct {
+	ID   sno.ID   `bson:"_id"`
+	Refs []sno.ID `bson:"refs"`
+}
+
+func marshal(t *testing.T, reg *bsoncodec.Registry, v interface{}) bson.Raw {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	vw, err := bsonrw.NewBSONValueWriter(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	enc, err := bson.NewEncoder(vw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := enc.SetRegistry(reg); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := enc.Encode(v); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func unmarshal(reg *bsoncodec.Registry, raw bson.Raw, v interface{}) error {
+	dec, err := bson.NewDecoder(bsonrw.NewBSONDocumentReader(raw))
+	if err != nil {
+		return err
+	}
+
+	if err := dec.SetRegistry(reg); err != nil {
+		return err
+	}
+
+	return dec.Decode(v)
+}
+
+func TestCodec_RoundTrip(t *testing.T) {
+	id := sno.ID{78, 111, 33, 96, 160, 255, 154, 10, 16, 51}
+
+	for _, c := range []struct {
+		name string
+		repr Representation
+		typ  bsontype.Type
+	}{
+		{"string", String, bsontype.String},
+		{"binary", Binary, bsontype.Binary},
+	} {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			reg := bson.NewRegistry()
+			codec := &Codec{Representation: c.repr}
+			reg.RegisterTypeEncoder(tID, codec)
+			reg.RegisterTypeDecoder(tID, codec)
+
+			src := record{ID: id, Refs: []sno.ID{id, {}}}
+			raw := marshal(t, reg, src)
+
+			v := raw.Lookup("_id")
+			if actual, expected := v.Type, c.typ; actual != expected {
+				t.Errorf("expected [%v], got [%v]", expected, actual)
+			}
+
+			switch c.repr {
+			case String:
+				if actual, expected := v.StringValue(), "brpk4q72xwf2m63l"; actual != expected {
+					t.Errorf("expected [%s], got [%s]", expected, actual)
+				}
+			case Binary:
+				if subtype, b := v.Binary(); subtype != bson.TypeBinaryGeneric || !bytes.Equal(b, id[:]) {
+					t.Errorf("expected [%#x, %v], got [%#x, %v]", bson.TypeBinaryGeneric, id[:], subtype, b)
+				}
+			}
+
+			// The zero ID maps to null, like it does with JSON.
+			if actual, expected := raw.Lookup("refs", "1").Type, bsontype.Null; actual != expected {
+				t.Errorf("expected [%v], got [%v]", expected, actual)
+			}
+
+			var actual record
+			if err := unmarshal(reg, raw, &actual); err != nil {
+				t.Fatal(err)
+			}
+
+			if actual.ID != src.ID || len(actual.Refs) != 2 || actual.Refs[0] != id || !actual.Refs[1].IsZero() {
+				t.Errorf("expected [%v], got [%v]", src, actual)
+			}
+		})
+	}
+}
+
+func TestCodec_DecodeEitherRepresentation(t *testing.T) {
+	id := sno.ID{78, 111, 33, 96, 160, 255, 154, 10, 16, 51}
+
+	for _, doc := range []bson.D{
+		{{Key: "_id", Value: "brpk4q72xwf2m63l"}},
+		{{Key: "_id", Value: primitive.Binary{Subtype: bson.TypeBinaryGeneric, Data: id[:]}}},
+	} {
+		raw, err := bson.Marshal(doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var actual record
+		if err := unmarshal(NewRegistry(), raw, &actual); err != nil {
+			t.Fatal(err)
+		}
+
+		if actual.ID != id {
+			t.Errorf("expected [%v], got [%v]", id, actual.ID)
+		}
+	}
+
+	raw, err := bson.Marshal(bson.D{{Key: "_id", Value: nil}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Pre-filled, so that decoding null must reset it.
+	actual := record{ID: id}
+	if err := unmarshal(NewRegistry(), raw, &actual); err != nil {
+		t.Fatal(err)
+	}
+
+	if !actual.ID.IsZero() {
+		t.Errorf("expected the zero ID, got [%v]", actual.ID)
+	}
+}
+
+func TestCodec_DecodeInvalid(t *testing.T) {
+	for _, c := range []struct {
+		name  string
+		value interface{}
+	}{
+		{"string-size", "brpk4q72xwf2m63"},
+		{"string-char", "brpk4q72xwf2m63!"},
+		{"binary-size", primitive.Binary{Data: []byte{1, 2, 3}}},
+		{"binary-subtype", primitive.Binary{Subtype: bson.TypeBinaryUUID, Data: make([]byte, 10)}},
+		{"int", int32(1)},
+	} {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			raw, err := bson.Marshal(bson.D{{Key: "_id", Value: c.value}})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var actual record
+			if err := unmarshal(NewRegistry(), raw, &actual); err == nil {
+				t.Errorf("expected error, got none (decoded [%v])", actual.ID)
+			}
+		})
+	}
+}
+
+func TestRegister_DefaultRepresentation(t *testing.T) {
+	defer func(r Representation) { DefaultRepresentation = r }(DefaultRepresentation)
+
+	id := sno.ID{78, 111, 33, 96, 160, 255, 154, 10, 16, 51}
+
+	if actual, expected := marshal(t, NewRegistry(), record{ID: id}).Lookup("_id").Type, bsontype.String; actual != expected {
+		t.Errorf("expected [%v], got [%v]", expected, actual)
+	}
+
+	DefaultRepresentation = Binary
+
+	if actual, expected := marshal(t, NewRegistry(), record{ID: id}).Lookup("_id").Type, bsontype.Binary; actual != expected {
+		t.Errorf("expected [%v], got [%v]", expected, actual)
+	}
+}
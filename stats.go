@@ -0,0 +1,191 @@
+package sno
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// statsWindow is the number of most recent timeframes the observed rate of generation
+	// gets averaged over - 64 time units, which equals 256msec.
+	statsWindow = 64
+
+	// Frame records get packed as the timeframe (in sno time units) shifted left by frameCountBits,
+	// ORed with the count of IDs generated in that timeframe (which can't exceed MaxSequence+1).
+	frameCountBits = 20
+	frameCountMask = 1<<frameCountBits - 1
+)
+
+// GeneratorStats contains utilization metrics of a Generator at some point in time.
+type GeneratorStats struct {
+	// Name is the name of the Generator (see GeneratorSnapshot.Name).
+	Name string `json:"name"`
+
+	// MaxRate is the configured throughput ceiling of the Generator in IDs per second,
+	// that is Cap() IDs in each of the 250 timeframes in a second.
+	MaxRate int `json:"maxRate"`
+
+	// ObservedRate is the rate of generation in IDs per second, as observed over
+	// the most recent 64 timeframes (256msec), including the current one.
+	ObservedRate int `json:"observedRate"`
+
+	// RegressionSleeps is the count of times generation calls slept because the wall clock regressed
+	// again before it caught up with the time of a previous regression (into a past where the Generator
+	// can not tick-tock again), and RegressionSleepTime the total time they slept for.
+	//
+	// Unlike the count of drifts (which counts tick-tocks), frequent sleeps indicate a pathologically
+	// unstable clock. Both are cumulative over the lifetime of the Generator.
+	RegressionSleeps    uint64        `json:"regressionSleeps"`
+	RegressionSleepTime time.Duration `json:"regressionSleepTime"`
+}
+
+// OverflowStats contains cumulative metrics of the sequence overflows of a Generator.
+//
+// See Generator.TakeOverflowStats.
+type OverflowStats struct {
+	// Episodes is the count of overflows that began - an overflow begins when the first call gets blocked
+	// and ends when the Generator declogs.
+	Episodes uint64 `json:"episodes"`
+
+	// Ticks is the count of overflow ticks that happened (roughly one per each 1msec spent overflowing).
+	Ticks uint64 `json:"ticks"`
+
+	// Peak is the highest count of concurrently blocked generation calls.
+	Peak uint32 `json:"peak"`
+}
+
+// RegressionEvent describes a wall clock regression detected between two consecutive snapshots
+// of a Generator. See DetectRegressions.
+type RegressionEvent struct {
+	// Index is the index of the snapshot the regression got detected at, i.e. the later one of the two.
+	Index int `json:"index"`
+
+	// WallHiBefore and WallHiAfter are the WallHi of the two snapshots (in sno time units).
+	WallHiBefore int64 `json:"wallHiBefore"`
+	WallHiAfter  int64 `json:"wallHiAfter"`
+
+	// Drifts is the count of regressions the Generator tick-tocked at in between the two snapshots.
+	// It may be 0 when WallHi decreased nonetheless (e.g. across a restart from an older snapshot).
+	Drifts uint32 `json:"drifts"`
+}
+
+// DetectRegressions scans the given series of snapshots of a single Generator and reports each pair
+// of consecutive snapshots in between which the wall clock regressed - that is, where WallHi decreased
+// or Drifts increased - e.g. to turn periodic logging of snapshots into an audit trail of regressions.
+//
+// The snapshots must be ordered by the time they got taken at (oldest first), which is not necessarily
+// the order of their Now or WallHi - both follow the wall clock, which is precisely what may regress.
+// Multiple regressions between the same two snapshots get reported as one event.
+func DetectRegressions(snaps []GeneratorSnapshot) (events []RegressionEvent) {
+	for i := 1; i < len(snaps); i++ {
+		prev, cur := &snaps[i-1], &snaps[i]
+
+		var drifts uint32
+		if cur.Drifts > prev.Drifts {
+			drifts = cur.Drifts - prev.Drifts
+		}
+
+		if cur.WallHi < prev.WallHi || drifts > 0 {
+			events = append(events, RegressionEvent{
+				Index:        i,
+				WallHiBefore: prev.WallHi,
+				WallHiAfter:  cur.WallHi,
+				Drifts:       drifts,
+			})
+		}
+	}
+
+	return
+}
+
+// TakeOverflowStats returns the overflow metrics accumulated since the previous call to TakeOverflowStats
+// (or since the Generator got created) and atomically resets them - in other words, the metrics get
+// reset on each read, so that each read reports only the delta since the last.
+//
+// If the Generator is overflowing at the time of the call, the count of currently blocked calls carries
+// over as the baseline Peak of the next window.
+func (g *Generator) TakeOverflowStats() (stats OverflowStats) {
+	g.seqOverflowCond.L.Lock()
+	stats = g.seqOverflowStats
+	g.seqOverflowStats = OverflowStats{
+		Peak: g.seqOverflowCount,
+	}
+	g.seqOverflowCond.L.Unlock()
+
+	return
+}
+
+// Stats returns the current utilization metrics of the Generator.
+//
+// The observed rate only accounts for IDs generated via New() - IDs generated with
+// user-specified timestamps do not belong to any timeframe of the Generator's own clock.
+func (g *Generator) Stats() GeneratorStats {
+	var (
+		wallNow = g.now()
+		wallHi  uint64
+		current uint64
+		count   uint64
+	)
+
+	// The sequence of leased Generators lies within their band rather than counting up from SequenceMin,
+	// so they keep count of the IDs of the current timeframe separately.
+	if g.leaser != nil {
+		g.leaseMu.Lock()
+		wallHi, current = atomic.LoadUint64(&g.wallHi), uint64(g.leaseLen)
+		g.leaseMu.Unlock()
+	} else {
+		wallHi = atomic.LoadUint64(&g.wallHi)
+		current = uint64(g.frameLen(atomic.LoadUint32(&g.seq), atomic.LoadUint32(&g.seqFirst)))
+	}
+
+	// The logical clock may run ahead of the wall clock, in which case it is our point of reference.
+	if g.logical && wallNow < wallHi {
+		wallNow = wallHi
+	}
+
+	// Counts of past timeframes only get recorded once the Generator progresses to a new timeframe,
+	// so the most recent one is accounted for separately.
+	if wallHi <= wallNow && wallNow-wallHi < statsWindow {
+		count = current
+	}
+
+	for i := range g.frames {
+		var (
+			frame = atomic.LoadUint64(&g.frames[i])
+			wall  = frame >> frameCountBits
+		)
+
+		// Records of timeframes outside of our window (including those in the future relative
+		// to wallNow, e.g. after a wall clock regression) simply get skipped.
+		if wall != wallHi && wall <= wallNow && wallNow-wall < statsWindow {
+			count += frame & frameCountMask
+		}
+	}
+
+	return GeneratorStats{
+		Name:         g.name,
+		MaxRate:      g.Cap() * 250,
+		ObservedRate: int(count * 250 / statsWindow),
+
+		RegressionSleeps:    atomic.LoadUint64(&g.regressionSleeps),
+		RegressionSleepTime: time.Duration(atomic.LoadUint64(&g.regressionSlept)),
+	}
+}
+
+// recordFrameLen stores the given count of IDs generated in the given timeframe.
+func (g *Generator) recordFrameLen(wall uint64, n uint32) {
+	atomic.StoreUint64(&g.frames[wall%statsWindow], wall<<frameCountBits|uint64(n))
+}
+
+// frameLen returns the number of IDs generated in a timeframe which started at the sequence first
+// and ended up at the given sequence.
+func (g *Generator) frameLen(seq, first uint32) int {
+	if seq > g.seqMax {
+		seq = g.seqMax
+	} else if seq < first {
+		// The sequence got reset to SequenceMin without a new timeframe being started (see seqOverflowLoop).
+		return 0
+	}
+
+	return int(seq-first) + 1
+}
@@ -0,0 +1,234 @@
+// +build test
+
+package sno
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/muyo/sno/internal"
+)
+
+func TestGenerator_Stats(t *testing.T) {
+	var (
+		perFrame = 10
+		g, err   = NewGenerator(&GeneratorSnapshot{
+			SequenceMin: 1024,
+			SequenceMax: 2047,
+		}, nil)
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wall := internal.Snotime()
+	snotime = staticTime
+
+	// Drive a known rate across the entire window - the last frame is the current one.
+	for f := 0; f < statsWindow; f++ {
+		atomic.StoreUint64(staticWallNow, wall+uint64(f))
+
+		for i := 0; i < perFrame; i++ {
+			g.New(255)
+		}
+	}
+
+	stats := g.Stats()
+
+	if actual, expected := stats.MaxRate, 1024*250; actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	if actual, expected := stats.ObservedRate, perFrame*250; actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	// Half of the window elapses without any generation.
+	atomic.AddUint64(staticWallNow, statsWindow/2)
+
+	if actual, expected := g.Stats().ObservedRate, perFrame*250/2; actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	// Everything we generated falls out of the window.
+	atomic.AddUint64(staticWallNow, statsWindow)
+
+	if actual, expected := g.Stats().ObservedRate, 0; actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	snotime = internal.Snotime
+}
+
+func TestGenerator_Stats_Leased(t *testing.T) {
+	var (
+		perFrame = 5
+		g, err   = NewGeneratorWithOptions(&GeneratorSnapshot{
+			SequenceMin: 1024,
+			SequenceMax: 2047,
+		}, &GeneratorOptions{
+			// A band far above SequenceMin.
+			Leaser: &memLeaser{next: 1536, size: 8, max: 2047},
+		}, nil)
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wall := internal.Snotime()
+	snotime = staticTime
+
+	for f := 0; f < statsWindow; f++ {
+		atomic.StoreUint64(staticWallNow, wall+uint64(f))
+
+		for i := 0; i < perFrame; i++ {
+			g.New(255)
+		}
+	}
+
+	if actual, expected := g.Stats().ObservedRate, perFrame*250; actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	snotime = internal.Snotime
+}
+
+func TestGenerator_Stats_Jitter(t *testing.T) {
+	clock := manualClock(1000)
+
+	g, err := NewGeneratorWithOptions(&GeneratorSnapshot{
+		Jitter: 60000,
+	}, &GeneratorOptions{Clock: clock.now}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// One ID per timeframe, each starting at a jittered sequence.
+	var last ID
+	for f := uint64(0); f < statsWindow; f++ {
+		clock.set(1000 + f)
+		last = g.New(255)
+	}
+
+	if actual, expected := g.Len(), 1; actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	if actual, expected := g.Stats().ObservedRate, 250; actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+
+	// Only what is left past the jittered start of the current timeframe is spare.
+	deadline := time.Unix(g.Epoch(), int64((1000+statsWindow)*TimeUnit))
+	if actual, expected := g.CapacityUntil(deadline), int64(MaxSequence-last.Sequence()); actual != expected {
+		t.Errorf("expected [%d], got [%d]", expected, actual)
+	}
+}
+
+func TestGenerator_TakeOverflowStats(t *testing.T) {
+	g, err := NewGenerator(&GeneratorSnapshot{
+		SequenceMin: 1024,
+		SequenceMax: 1039,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if actual, expected := g.TakeOverflowStats(), (OverflowStats{}); actual != expected {
+		t.Errorf("expected [%+v], got [%+v]", expected, actual)
+	}
+
+	overflow := func() {
+		var wg sync.WaitGroup
+		wg.Add(4)
+
+		for i := 0; i < 4; i++ {
+			go func() {
+				for j := 0; j < 4*g.Cap(); j++ {
+					g.New(255)
+				}
+				wg.Done()
+			}()
+		}
+
+		wg.Wait()
+
+		// The overflow loop keeps ticking until it notices it has declogged. We wait for that
+		// to happen in order to get deterministic windows.
+		for {
+			g.seqOverflowCond.L.Lock()
+			done := g.seqOverflowTicker == nil
+			g.seqOverflowCond.L.Unlock()
+
+			if done {
+				return
+			}
+
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	overflow()
+
+	first := g.TakeOverflowStats()
+	if first.Episodes == 0 {
+		t.Error("expected at least one overflow episode, got none")
+	}
+
+	if first.Ticks == 0 {
+		t.Error("expected at least one overflow tick, got none")
+	}
+
+	if first.Peak == 0 || first.Peak > 4 {
+		t.Errorf("expected a peak in range [1, 4], got [%d]", first.Peak)
+	}
+
+	// Metrics reset on read.
+	if actual, expected := g.TakeOverflowStats(), (OverflowStats{}); actual != expected {
+		t.Errorf("expected [%+v], got [%+v]", expected, actual)
+	}
+
+	overflow()
+
+	if second := g.TakeOverflowStats(); second.Episodes == 0 || second.Ticks == 0 || second.Peak == 0 {
+		t.Errorf("expected non-zero metrics for the second window, got [%+v]", second)
+	}
+}
+
+func TestDetectRegressions(t *testing.T) {
+	clock := manualClock(1000)
+
+	g, err := NewGeneratorWithOptions(&GeneratorSnapshot{
+		Partition: Partition{'D', 'R'},
+	}, &GeneratorOptions{Clock: clock.now}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var snaps []GeneratorSnapshot
+
+	for _, wall := range []uint64{1000, 1005, 995, 1010} {
+		clock.set(wall)
+		g.New(255)
+		snaps = append(snaps, g.Snapshot())
+	}
+
+	// A restart from an older snapshot regresses WallHi without any drift being recorded.
+	snaps = append(snaps, snaps[0])
+
+	expected := []RegressionEvent{
+		{Index: 2, WallHiBefore: 1005, WallHiAfter: 995, Drifts: 1},
+		{Index: 4, WallHiBefore: 1010, WallHiAfter: 1000, Drifts: 0},
+	}
+
+	if actual := DetectRegressions(snaps); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected [%+v], got [%+v]", expected, actual)
+	}
+
+	if actual := DetectRegressions(snaps[:2]); actual != nil {
+		t.Errorf("expected [nil], got [%+v]", actual)
+	}
+}